@@ -0,0 +1,143 @@
+package abb
+
+import (
+	"fmt"
+	"strconv"
+	"time"
+
+	"github.com/atmassey/abb-lib-rws/structures"
+)
+
+// Calibration methods supported by a CalibrationSession.
+const (
+	CalibrationRevCounter = "revcounter"
+	CalibrationFine       = "fine"
+)
+
+// CalibrationSession walks the axes of a mechanical unit through a guided calibration
+// procedure, e.g. after a motor or SMB board has been replaced.
+type CalibrationSession struct {
+	Client   *Client
+	MechUnit string
+	Method   string
+	Axes     []int
+	// Commutate will also update the commutation offset before calibrating each axis.
+	Commutate bool
+	opmode    string
+}
+
+// NewCalibrationSession creates a calibration session for the given axes of a mechanical unit.
+// Method must be either CalibrationRevCounter or CalibrationFine. Without axes every axis of the
+// mechanical unit is calibrated.
+func (c *Client) NewCalibrationSession(MechUnit string, Method string, Axes ...int) *CalibrationSession {
+	return &CalibrationSession{
+		Client:   c,
+		MechUnit: MechUnit,
+		Method:   Method,
+		Axes:     Axes,
+	}
+}
+
+// CheckPreconditions verifies the selected axes exist on the mechanical unit and the controller
+// is in manual mode with the motors off.
+// Mastership of the motion domain is checked by Run when it is requested.
+func (s *CalibrationSession) CheckPreconditions() error {
+	if s.Method != CalibrationRevCounter && s.Method != CalibrationFine {
+		return fmt.Errorf("invalid calibration method: %s", s.Method)
+	}
+	mechUnit, err := s.Client.getMechUnit(s.MechUnit)
+	if err != nil {
+		return err
+	}
+	axes, err := strconv.Atoi(mechUnit.Axes)
+	if err != nil || axes < 1 {
+		return fmt.Errorf("invalid number of axes for %s: %q", s.MechUnit, mechUnit.Axes)
+	}
+	if len(s.Axes) == 0 {
+		for axis := 1; axis <= axes; axis++ {
+			s.Axes = append(s.Axes, axis)
+		}
+	}
+	for _, axis := range s.Axes {
+		if axis < 1 || axis > axes {
+			return fmt.Errorf("invalid axis %d, %s has %d axes", axis, s.MechUnit, axes)
+		}
+	}
+	mode, err := s.Client.GetOperationMode()
+	if err != nil {
+		return err
+	}
+	if mode != "MANR" && mode != "MANF" {
+		return fmt.Errorf("controller must be in manual mode, current mode: %s", mode)
+	}
+	state, err := s.Client.GetControllerState()
+	if err != nil {
+		return err
+	}
+	if state != "motoroff" {
+		return fmt.Errorf("motors must be off, current state: %s", state)
+	}
+	s.opmode = mode
+	return nil
+}
+
+// Run checks the preconditions, requests motion mastership and calibrates every selected axis.
+// The calibration status of each axis is read back after the update and collected in the report.
+// A failing axis does not stop the procedure; its error is recorded in the report instead.
+// If releasing mastership fails afterwards, the report is returned together with that error.
+func (s *CalibrationSession) Run() (Report *structures.CalibrationReport, Err error) {
+	report := structures.CalibrationReport{
+		MechUnit:  s.MechUnit,
+		Method:    s.Method,
+		StartTime: time.Now(),
+	}
+	err := s.CheckPreconditions()
+	if err != nil {
+		return nil, err
+	}
+	report.OperationMode = s.opmode
+	err = s.Client.RequestMastershipIndividual("motion")
+	if err != nil {
+		return nil, fmt.Errorf("unable to get motion mastership: %w", err)
+	}
+	defer func() {
+		if err := s.Client.ReleaseMastershipIndividual("motion"); err != nil && Err == nil {
+			Err = fmt.Errorf("unable to release motion mastership: %w", err)
+		}
+	}()
+	for _, axis := range s.Axes {
+		report.Axes = append(report.Axes, s.calibrateAxis(axis))
+	}
+	report.EndTime = time.Now()
+	return &report, nil
+}
+
+// calibrateAxis updates a single axis and reads back its calibration status.
+func (s *CalibrationSession) calibrateAxis(Axis int) structures.AxisCalibrationResult {
+	result := structures.AxisCalibrationResult{Axis: Axis, Action: s.Method}
+	axis := strconv.Itoa(Axis)
+	if s.Commutate {
+		if err := s.Client.UpdateCommutate(s.MechUnit, axis); err != nil {
+			result.Error = fmt.Sprintf("commutate: %s", err)
+			return result
+		}
+	}
+	var err error
+	switch s.Method {
+	case CalibrationRevCounter:
+		err = s.Client.UpdateSyncRevCounter(s.MechUnit, axis)
+	case CalibrationFine:
+		err = s.Client.SetFineCalibration(s.MechUnit, Axis)
+	}
+	if err != nil {
+		result.Error = err.Error()
+		return result
+	}
+	calibration, err := s.Client.GetAxisCalibration(s.MechUnit, Axis)
+	if err != nil {
+		result.Error = fmt.Sprintf("read back: %s", err)
+		return result
+	}
+	result.Calibration = calibration
+	return result
+}
@@ -0,0 +1,168 @@
+package abb
+
+import (
+	"bytes"
+	"fmt"
+	"net/http"
+	"net/url"
+	"sort"
+
+	"github.com/atmassey/abb-lib-rws/structures"
+)
+
+// ListCfgDomains returns the configuration domains on the controller, e.g. EIO, MOC, SIO, SYS, PROC and MMC.
+func (c *Client) ListCfgDomains() ([]string, error) {
+	return c.listCfgTitles("http://" + c.Host + "/rw/cfg?json=1")
+}
+
+// ListCfgTypes returns the types of a configuration domain.
+// Example: Domain = EIO -> EIO_SIGNAL, EIO_CROSS, INDUSTRIAL_NETWORK, ...
+func (c *Client) ListCfgTypes(Domain string) ([]string, error) {
+	return c.listCfgTitles("http://" + c.Host + "/rw/cfg/" + Domain + "?json=1")
+}
+
+// listCfgTitles is a helper function that returns the titles of a configuration listing, following the paging links.
+func (c *Client) listCfgTitles(URL string) ([]string, error) {
+	var titles []string
+	next := URL
+	for next != "" {
+		var page structures.CfgListJson
+		var err error
+		next, err = c.getJSONPage(next, &page)
+		if err != nil {
+			return nil, err
+		}
+		for _, state := range page.Embedded.State {
+			titles = append(titles, state.Title)
+		}
+	}
+	return titles, nil
+}
+
+// GetCfgInstance returns a single configuration instance with its attributes.
+// Example: Domain = EIO, Type = EIO_SIGNAL, Name = doTest1
+func (c *Client) GetCfgInstance(Domain string, Type string, Name string) (*structures.CfgInstance, error) {
+	var page structures.CfgInstanceJson
+	_, err := c.getJSONPage("http://"+c.Host+"/rw/cfg/"+Domain+"/"+Type+"/instances/"+Name+"?json=1", &page)
+	if err != nil {
+		return nil, err
+	}
+	if len(page.Embedded.State) == 0 {
+		return nil, fmt.Errorf("instance not found: %s/%s/%s", Domain, Type, Name)
+	}
+	instance := cfgInstanceFromJson(Domain, Type, page.Embedded.State[0])
+	return &instance, nil
+}
+
+// ListCfgInstances returns all instances of a configuration type, following the paging links.
+// Example: Domain = EIO, Type = EIO_CROSS
+func (c *Client) ListCfgInstances(Domain string, Type string) ([]structures.CfgInstance, error) {
+	var instances []structures.CfgInstance
+	next := "http://" + c.Host + "/rw/cfg/" + Domain + "/" + Type + "/instances?json=1"
+	for next != "" {
+		var page structures.CfgInstancesJson
+		var err error
+		next, err = c.getJSONPage(next, &page)
+		if err != nil {
+			return nil, err
+		}
+		for _, state := range page.Embedded.State {
+			instances = append(instances, cfgInstanceFromJson(Domain, Type, state))
+		}
+	}
+	return instances, nil
+}
+
+// CreateCfgInstance creates a new configuration instance and sets its attributes.
+// Mastership of the cfg domain is required, see RequestMastershipIndividual.
+func (c *Client) CreateCfgInstance(Domain string, Type string, Name string, Attributes structures.CfgAttributes) error {
+	body := url.Values{}
+	body.Add("name", Name)
+	err := c.postCfg("/rw/cfg/"+Domain+"/"+Type+"/instances", "create-default", body, http.StatusCreated)
+	if err != nil {
+		return err
+	}
+	if len(Attributes) == 0 {
+		return nil
+	}
+	err = c.UpdateCfgInstance(Domain, Type, Name, Attributes)
+	if err != nil {
+		if deleteErr := c.DeleteCfgInstance(Domain, Type, Name); deleteErr != nil {
+			return fmt.Errorf("%w (removing the new instance failed: %s)", err, deleteErr)
+		}
+		return err
+	}
+	return nil
+}
+
+// UpdateCfgInstance sets the given attributes of a configuration instance, other attributes are left unchanged.
+// Mastership of the cfg domain is required, see RequestMastershipIndividual.
+func (c *Client) UpdateCfgInstance(Domain string, Type string, Name string, Attributes structures.CfgAttributes) error {
+	body := url.Values{}
+	keys := make([]string, 0, len(Attributes))
+	for key := range Attributes {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	for _, key := range keys {
+		body.Add(key, Attributes[key])
+	}
+	return c.postCfg("/rw/cfg/"+Domain+"/"+Type+"/instances/"+Name, "set", body, http.StatusNoContent)
+}
+
+// DeleteCfgInstance removes a configuration instance.
+// Mastership of the cfg domain is required, see RequestMastershipIndividual.
+func (c *Client) DeleteCfgInstance(Domain string, Type string, Name string) error {
+	c.Client = c.DigestAuthenticate()
+	req, err := http.NewRequest("DELETE", "http://"+c.Host+"/rw/cfg/"+Domain+"/"+Type+"/instances/"+Name, nil)
+	if err != nil {
+		return err
+	}
+	resp, err := c.Client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer closeErrorCheck(resp.Body)
+	if resp.StatusCode != http.StatusNoContent && resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("HTTP Status Code: %d", resp.StatusCode)
+	}
+	return nil
+}
+
+// postCfg is a helper function that posts a configuration action and checks the expected status code.
+func (c *Client) postCfg(Path string, Action string, Body url.Values, Status int) error {
+	c.Client = c.DigestAuthenticate()
+	req, err := http.NewRequest("POST", "http://"+c.Host+Path, bytes.NewBufferString(Body.Encode()))
+	if err != nil {
+		return err
+	}
+	q := req.URL.Query()
+	q.Add("action", Action)
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	req.URL.RawQuery = q.Encode()
+	resp, err := c.Client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer closeErrorCheck(resp.Body)
+	if resp.StatusCode != Status && resp.StatusCode != http.StatusNoContent {
+		return fmt.Errorf("HTTP Status Code: %d", resp.StatusCode)
+	}
+	return nil
+}
+
+func cfgInstanceFromJson(Domain string, Type string, State structures.CfgInstanceJsonMeta) structures.CfgInstance {
+	instance := structures.CfgInstance{
+		Domain:     Domain,
+		Type:       Type,
+		Name:       State.Title,
+		Attributes: make(structures.CfgAttributes),
+	}
+	for _, attribute := range State.Attrib {
+		instance.Attributes[attribute.Title] = attribute.Value
+	}
+	if name, ok := instance.Attributes["Name"]; ok && name != "" {
+		instance.Name = name
+	}
+	return instance
+}
@@ -0,0 +1,162 @@
+package abb
+
+import (
+	"bytes"
+	"fmt"
+	"net/http"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strconv"
+
+	"github.com/atmassey/abb-lib-rws/cfgfile"
+	"github.com/atmassey/abb-lib-rws/structures"
+)
+
+// Load modes for LoadCfgFile.
+const (
+	// CfgLoadAdd adds new instances and keeps existing instances unchanged.
+	CfgLoadAdd = "add"
+	// CfgLoadReplace replaces the whole domain with the content of the file.
+	CfgLoadReplace = "replace"
+	// CfgLoadAddWithReset adds new instances and overwrites existing instances with the same name.
+	CfgLoadAddWithReset = "add-with-reset"
+)
+
+// LoadCfgFile uploads a local configuration file to $TEMP and loads it into the configuration database.
+// Every instance in the file is compared with the controller afterwards, instances that are missing
+// or differ are reported as rejected. In add mode instances that already existed are left unchanged
+// by the controller and are counted as skipped instead. A warm start (Warmstart) is required when RestartRequired is set.
+// Mastership of the cfg domain is required, see RequestMastershipIndividual.
+// Example: Path = backup/SYSPAR/EIO.cfg, Mode = CfgLoadAddWithReset
+func (c *Client) LoadCfgFile(Path string, Mode string) (*structures.CfgLoadResult, error) {
+	switch Mode {
+	case CfgLoadAdd, CfgLoadReplace, CfgLoadAddWithReset:
+	default:
+		return nil, fmt.Errorf("invalid load mode: %s", Mode)
+	}
+	data, err := os.ReadFile(Path)
+	if err != nil {
+		return nil, err
+	}
+	file, err := cfgfile.Parse(bytes.NewReader(data))
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", Path, err)
+	}
+	before, err := c.cfgTypeInstances(file)
+	if err != nil {
+		return nil, err
+	}
+	err = c.UploadFile(Path, "$TEMP")
+	if err != nil {
+		return nil, err
+	}
+	remote := "$TEMP/" + filepath.Base(Path)
+	body := url.Values{}
+	body.Add("filepath", remote)
+	body.Add("action-type", Mode)
+	err = c.postCfg("/rw/cfg", "load", body, http.StatusNoContent)
+	if deleteErr := c.DeleteFile(remote); deleteErr != nil && err == nil {
+		err = deleteErr
+	}
+	if err != nil {
+		return nil, err
+	}
+	after, err := c.cfgTypeInstances(file)
+	if err != nil {
+		return nil, err
+	}
+	result := structures.CfgLoadResult{Domain: file.Domain, Mode: Mode}
+	for _, typ := range file.Types {
+		names := make(map[string]bool)
+		for _, instance := range typ.Instances {
+			name := instance.Name()
+			names[name] = true
+			if _, existed := before[typ.Name][name]; existed && Mode == CfgLoadAdd {
+				result.Skipped++
+				continue
+			}
+			reason := cfgInstanceMismatch(instance, after[typ.Name][name])
+			if reason != "" {
+				result.Rejected = append(result.Rejected, structures.CfgRejectedInstance{Type: typ.Name, Name: name, Reason: reason})
+				continue
+			}
+			result.Accepted++
+			if cfgInstanceMismatch(instance, before[typ.Name][name]) != "" {
+				result.RestartRequired = true
+			}
+		}
+		if Mode == CfgLoadReplace {
+			for name := range before[typ.Name] {
+				if !names[name] {
+					result.RestartRequired = true
+				}
+			}
+		}
+	}
+	return &result, nil
+}
+
+// SaveCfgDomain saves a configuration domain on the controller and downloads it to a local file.
+// Example: Domain = EIO, Path = EIO.cfg
+func (c *Client) SaveCfgDomain(Domain string, Path string) error {
+	remote := "$TEMP/" + Domain + ".cfg"
+	body := url.Values{}
+	body.Add("filepath", remote)
+	err := c.postCfg("/rw/cfg/"+Domain, "saveas", body, http.StatusNoContent)
+	if err != nil {
+		return err
+	}
+	err = c.GetFile(remote, Path)
+	if deleteErr := c.DeleteFile(remote); deleteErr != nil && err == nil {
+		err = deleteErr
+	}
+	return err
+}
+
+// cfgTypeInstances is a helper function that returns the controller instances of every type in a file by type and name.
+func (c *Client) cfgTypeInstances(File *cfgfile.File) (map[string]map[string]structures.CfgAttributes, error) {
+	types := make(map[string]map[string]structures.CfgAttributes)
+	for _, typ := range File.Types {
+		instances, err := c.ListCfgInstances(File.Domain, typ.Name)
+		if err != nil {
+			return nil, err
+		}
+		types[typ.Name] = make(map[string]structures.CfgAttributes)
+		for _, instance := range instances {
+			types[typ.Name][instance.Name] = instance.Attributes
+		}
+	}
+	return types, nil
+}
+
+// cfgInstanceMismatch returns why the controller attributes don't match an instance of a file, "" if they do.
+func cfgInstanceMismatch(Instance *cfgfile.Instance, Attributes structures.CfgAttributes) string {
+	if Attributes == nil {
+		return "instance not found on the controller"
+	}
+	for _, attribute := range Instance.Attributes() {
+		value, ok := Attributes[attribute.Name]
+		switch {
+		case attribute.IsFlag():
+			if ok && value != "" && !cfgBool(value) {
+				return fmt.Sprintf("flag %s is %q", attribute.Name, value)
+			}
+		case !ok:
+			return fmt.Sprintf("attribute %s is missing", attribute.Name)
+		case !cfgValuesEqual(attribute.Value, value):
+			return fmt.Sprintf("attribute %s is %q, expected %q", attribute.Name, value, attribute.Value)
+		}
+	}
+	return ""
+}
+
+// cfgValuesEqual compares two attribute values, numbers are compared by value, e.g. 10 and 10.0.
+func cfgValuesEqual(A string, B string) bool {
+	if A == B {
+		return true
+	}
+	a, errA := strconv.ParseFloat(A, 64)
+	b, errB := strconv.ParseFloat(B, 64)
+	return errA == nil && errB == nil && a == b
+}
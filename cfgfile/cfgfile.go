@@ -0,0 +1,243 @@
+// Package cfgfile reads and writes ABB configuration files (SYSPAR/*.cfg) such as EIO.cfg, MOC.cfg,
+// SYS.cfg and PROC.cfg. Comments, blank lines and the order of types, instances and attributes are
+// kept, so a file that is parsed and written back without edits is byte-for-byte identical.
+// Only instances that are edited are formatted again.
+package cfgfile
+
+import (
+	"bytes"
+	"io"
+	"strconv"
+	"strings"
+)
+
+// indent is the indentation ABB uses for instance lines.
+const indent = "      "
+
+// lineWidth is the width after which an edited instance is continued on the next line.
+const lineWidth = 80
+
+// File is a parsed configuration file. A file holds a single domain, e.g. EIO.
+type File struct {
+	// Domain is the first field of the header, e.g. EIO for the header EIO:CFG_1.0:6:1::
+	Domain string
+	Header string
+	Types  []*Type
+
+	headerLines []string
+	trailer     []string
+	eol         string
+}
+
+// Type is a configuration type with its instances, e.g. EIO_SIGNAL.
+type Type struct {
+	Name      string
+	Instances []*Instance
+
+	lines []string
+}
+
+// Instance is a configuration instance, e.g. -Name "do1" -SignalType "DO" -Device "d651".
+type Instance struct {
+	attributes []Attribute
+	prefix     []string
+	lines      []string
+}
+
+// Attribute is a single attribute of an instance. An attribute without a value is a flag, e.g. -Invert.
+type Attribute struct {
+	Name  string
+	Value string
+	// Quoted is set for values written in double quotes.
+	Quoted bool
+}
+
+// IsFlag reports whether the attribute is written without a value.
+func (a Attribute) IsFlag() bool {
+	return !a.Quoted && a.Value == ""
+}
+
+func (a Attribute) String() string {
+	switch {
+	case a.Quoted:
+		return "-" + a.Name + " \"" + a.Value + "\""
+	case a.Value == "":
+		return "-" + a.Name
+	default:
+		return "-" + a.Name + " " + a.Value
+	}
+}
+
+// Type returns the type with the given name, or nil if the file does not contain it.
+func (f *File) Type(Name string) *Type {
+	for _, t := range f.Types {
+		if t.Name == Name {
+			return t
+		}
+	}
+	return nil
+}
+
+// AddType appends a new empty type to the file, or returns the existing type with that name.
+func (f *File) AddType(Name string) *Type {
+	if t := f.Type(Name); t != nil {
+		return t
+	}
+	t := &Type{Name: Name, lines: []string{"#", Name + ":"}}
+	f.Types = append(f.Types, t)
+	return t
+}
+
+// Bytes returns the encoded file.
+func (f *File) Bytes() []byte {
+	var b bytes.Buffer
+	f.WriteTo(&b)
+	return b.Bytes()
+}
+
+// WriteTo writes the encoded file to W.
+func (f *File) WriteTo(W io.Writer) (int64, error) {
+	var lines []string
+	lines = append(lines, f.headerLines...)
+	for _, t := range f.Types {
+		lines = append(lines, t.lines...)
+		for _, instance := range t.Instances {
+			lines = append(lines, instance.prefix...)
+			if instance.lines == nil {
+				instance.lines = instance.format()
+			}
+			lines = append(lines, instance.lines...)
+		}
+	}
+	lines = append(lines, f.trailer...)
+	n, err := io.WriteString(W, strings.Join(lines, f.eol))
+	return int64(n), err
+}
+
+// Find returns the instance with the given name, or nil if there is none.
+func (t *Type) Find(Name string) *Instance {
+	for _, instance := range t.Instances {
+		if instance.Name() == Name {
+			return instance
+		}
+	}
+	return nil
+}
+
+// Add appends a new instance with the given attributes to the type.
+func (t *Type) Add(Attributes ...Attribute) *Instance {
+	instance := &Instance{attributes: Attributes, prefix: []string{""}}
+	t.Instances = append(t.Instances, instance)
+	return instance
+}
+
+// Remove deletes an instance together with the blank lines and comments in front of it.
+// It returns false if the instance does not belong to the type.
+func (t *Type) Remove(Instance *Instance) bool {
+	for i, instance := range t.Instances {
+		if instance == Instance {
+			t.Instances = append(t.Instances[:i], t.Instances[i+1:]...)
+			return true
+		}
+	}
+	return false
+}
+
+// Name returns the name of the instance. Most domains use -Name, MOC uses -name.
+func (i *Instance) Name() string {
+	if name, ok := i.Get("Name"); ok {
+		return name
+	}
+	name, _ := i.Get("name")
+	return name
+}
+
+// Attributes returns the attributes of the instance in file order.
+func (i *Instance) Attributes() []Attribute {
+	return append([]Attribute(nil), i.attributes...)
+}
+
+// Map returns the attributes of the instance by name, flags have an empty value.
+func (i *Instance) Map() map[string]string {
+	attributes := make(map[string]string, len(i.attributes))
+	for _, attribute := range i.attributes {
+		attributes[attribute.Name] = attribute.Value
+	}
+	return attributes
+}
+
+// Get returns the value of an attribute and whether the instance has it.
+func (i *Instance) Get(Name string) (string, bool) {
+	for _, attribute := range i.attributes {
+		if attribute.Name == Name {
+			return attribute.Value, true
+		}
+	}
+	return "", false
+}
+
+// Set changes the value of an attribute, or appends it if the instance does not have it yet.
+// Existing attributes keep their quoting, new attributes are quoted unless the value is a number.
+func (i *Instance) Set(Name string, Value string) {
+	for n, attribute := range i.attributes {
+		if attribute.Name == Name {
+			if attribute.IsFlag() {
+				attribute.Quoted = !isNumber(Value)
+			}
+			attribute.Value = Value
+			i.attributes[n] = attribute
+			i.lines = nil
+			return
+		}
+	}
+	i.attributes = append(i.attributes, Attribute{Name: Name, Value: Value, Quoted: !isNumber(Value)})
+	i.lines = nil
+}
+
+// SetFlag adds a flag attribute without a value, e.g. -Invert.
+func (i *Instance) SetFlag(Name string) {
+	for n, attribute := range i.attributes {
+		if attribute.Name == Name {
+			i.attributes[n] = Attribute{Name: Name}
+			i.lines = nil
+			return
+		}
+	}
+	i.attributes = append(i.attributes, Attribute{Name: Name})
+	i.lines = nil
+}
+
+// Delete removes an attribute. It returns false if the instance does not have it.
+func (i *Instance) Delete(Name string) bool {
+	for n, attribute := range i.attributes {
+		if attribute.Name == Name {
+			i.attributes = append(i.attributes[:n], i.attributes[n+1:]...)
+			i.lines = nil
+			return true
+		}
+	}
+	return false
+}
+
+// format encodes the instance in ABB style, continuing the line with a backslash when it gets too long.
+func (i *Instance) format() []string {
+	var lines []string
+	line := indent
+	for _, attribute := range i.attributes {
+		text := attribute.String()
+		if line != indent && len(line)+1+len(text)+2 > lineWidth {
+			lines = append(lines, line+" \\")
+			line = indent
+		}
+		if line != indent {
+			line += " "
+		}
+		line += text
+	}
+	return append(lines, line)
+}
+
+func isNumber(Value string) bool {
+	_, err := strconv.ParseFloat(Value, 64)
+	return err == nil
+}
@@ -0,0 +1,115 @@
+package cfgfile
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+)
+
+const sampleMOC = `MOC:CFG_1.0:6:0::
+#
+# Motion configuration
+#
+MOTION_SYSTEM:
+
+      -name "system_1" -min_temp_cabinet 5 -max_temp_cabinet 45\
+      -min_temp_robot 5 -max_temp_robot 45
+#
+ARM:
+
+      -name "rob1_1" -use_arm_type "ROB1_1" -upper_joint_bound 2.87979\
+      -lower_joint_bound -2.87979
+
+      -name "rob1_2" -use_arm_type "ROB1_2" -use_check_point "rob1_2"
+#
+ROBOT:
+
+      -name "ROB_1" -use_robot_type "ROB1_120_0.58_3_TypeA" -independent_joint_on
+`
+
+// sampleEIO is an excerpt of an EIO.cfg saved by RobotWare, continued lines end with a space and a backslash.
+const sampleEIO = `EIO:CFG_1.0:6:1::
+#
+SYSSIG_OUT:
+
+      -Status "MotorOn" -Signal "doMotorOn"
+#
+EIO_SIGNAL:
+
+      -Name "doGripClose" -SignalType "DO" -Device "d652" -DeviceMap "0" \
+      -Category "Gripper" -Access "All"
+
+      -Name "giProgNo" -SignalType "GI" -Device "PN_Internal_Device" \
+      -DeviceMap "8-15" -Category "Cell" -MaxLog 255 -MaxPhys 255 \
+      -MaxBitVal 255
+`
+
+func TestRoundTrip(t *testing.T) {
+	for _, sample := range []string{sampleMOC, strings.ReplaceAll(sampleMOC, "\n", "\r\n"), strings.TrimSuffix(sampleMOC, "\n")} {
+		file, err := Parse(strings.NewReader(sample))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !bytes.Equal(file.Bytes(), []byte(sample)) {
+			t.Errorf("round trip changed the file:\n%q", file.Bytes())
+		}
+	}
+}
+
+func TestRoundTripEditedContinuation(t *testing.T) {
+	file, err := Parse(strings.NewReader(sampleEIO))
+	if err != nil {
+		t.Fatal(err)
+	}
+	signals := file.Type("EIO_SIGNAL")
+	if value, _ := signals.Find("giProgNo").Get("MaxBitVal"); value != "255" {
+		t.Fatalf("unexpected MaxBitVal: %s", value)
+	}
+	// setting an attribute to its current value reformats the instances
+	signals.Find("doGripClose").Set("Access", "All")
+	signals.Find("giProgNo").Set("MaxBitVal", "255")
+	if got := string(file.Bytes()); got != sampleEIO {
+		t.Errorf("reformatted instances differ from the original:\n%s", got)
+	}
+}
+
+func TestParse(t *testing.T) {
+	file, err := Parse(strings.NewReader(sampleMOC))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if file.Domain != "MOC" || len(file.Types) != 3 {
+		t.Fatalf("unexpected file: %+v", file)
+	}
+	arm := file.Type("ARM")
+	if arm == nil || len(arm.Instances) != 2 {
+		t.Fatalf("unexpected ARM type: %+v", arm)
+	}
+	if value, _ := arm.Find("rob1_1").Get("lower_joint_bound"); value != "-2.87979" {
+		t.Errorf("unexpected lower_joint_bound: %s", value)
+	}
+	attributes := file.Type("ROBOT").Instances[0].Attributes()
+	if last := attributes[len(attributes)-1]; last.Name != "independent_joint_on" || !last.IsFlag() {
+		t.Errorf("unexpected flag: %+v", last)
+	}
+}
+
+func TestEdit(t *testing.T) {
+	file, err := Parse(strings.NewReader(sampleMOC))
+	if err != nil {
+		t.Fatal(err)
+	}
+	arm := file.Type("ARM")
+	arm.Find("rob1_2").Set("use_check_point", "rob1_3")
+	arm.Remove(arm.Find("rob1_1"))
+	file.Type("ROBOT").Add(Attribute{Name: "name", Value: "ROB_2", Quoted: true})
+	expected := strings.Replace(sampleMOC, `      -name "rob1_1" -use_arm_type "ROB1_1" -upper_joint_bound 2.87979\
+      -lower_joint_bound -2.87979
+
+`, "", 1)
+	expected = strings.Replace(expected, `-use_check_point "rob1_2"`, `-use_check_point "rob1_3"`, 1)
+	expected = strings.TrimSuffix(expected, "\n") + "\n\n      -name \"ROB_2\"\n"
+	if got := string(file.Bytes()); got != expected {
+		t.Errorf("unexpected output:\n%s", got)
+	}
+}
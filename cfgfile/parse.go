@@ -0,0 +1,122 @@
+package cfgfile
+
+import (
+	"fmt"
+	"io"
+	"strings"
+	"unicode"
+)
+
+// Parse decodes a configuration file. Lines ending in a backslash are continued on the next line,
+// instances are separated by blank lines and comments start with #.
+func Parse(R io.Reader) (*File, error) {
+	data, err := io.ReadAll(R)
+	if err != nil {
+		return nil, err
+	}
+	file := File{eol: "\n"}
+	if strings.Contains(string(data), "\r\n") {
+		file.eol = "\r\n"
+	}
+	lines := strings.Split(string(data), file.eol)
+	var current *Type
+	var pending []string
+	for n := 0; n < len(lines); n++ {
+		line := lines[n]
+		trimmed := strings.TrimSpace(line)
+		switch {
+		case trimmed == "" || strings.HasPrefix(trimmed, "#"):
+			pending = append(pending, line)
+		case file.Header == "":
+			file.Header = trimmed
+			file.Domain = strings.SplitN(trimmed, ":", 2)[0]
+			file.headerLines = append(pending, line)
+			pending = nil
+		case strings.HasPrefix(trimmed, "-"):
+			if current == nil {
+				return nil, fmt.Errorf("line %d: instance outside of a type", n+1)
+			}
+			start := n
+			text := ""
+			for strings.HasSuffix(strings.TrimRight(lines[n], " \t"), "\\") && n+1 < len(lines) {
+				text += strings.TrimSuffix(strings.TrimRight(lines[n], " \t"), "\\") + " "
+				n++
+			}
+			text += lines[n]
+			attributes, err := parseAttributes(text)
+			if err != nil {
+				return nil, fmt.Errorf("line %d: %w", start+1, err)
+			}
+			current.Instances = append(current.Instances, &Instance{
+				attributes: attributes,
+				prefix:     pending,
+				lines:      lines[start : n+1],
+			})
+			pending = nil
+		case strings.HasSuffix(trimmed, ":") && !strings.ContainsAny(trimmed, " \t\""):
+			current = &Type{Name: strings.TrimSuffix(trimmed, ":"), lines: append(pending, line)}
+			file.Types = append(file.Types, current)
+			pending = nil
+		default:
+			return nil, fmt.Errorf("line %d: unexpected content %q", n+1, trimmed)
+		}
+	}
+	if file.Header == "" {
+		return nil, fmt.Errorf("missing header")
+	}
+	file.trailer = pending
+	return &file, nil
+}
+
+// parseAttributes splits an instance into its attributes, e.g. -Name "do1" -Invert -MinLog -10.
+func parseAttributes(Text string) ([]Attribute, error) {
+	var attributes []Attribute
+	Text = strings.TrimRight(Text, "\r")
+	i := 0
+	skipSpace := func() {
+		for i < len(Text) && (Text[i] == ' ' || Text[i] == '\t' || Text[i] == '\r') {
+			i++
+		}
+	}
+	for {
+		skipSpace()
+		if i >= len(Text) {
+			return attributes, nil
+		}
+		if Text[i] != '-' {
+			return nil, fmt.Errorf("expected attribute at %q", Text[i:])
+		}
+		start := i + 1
+		for i < len(Text) && Text[i] != ' ' && Text[i] != '\t' {
+			i++
+		}
+		attribute := Attribute{Name: Text[start:i]}
+		if attribute.Name == "" {
+			return nil, fmt.Errorf("empty attribute name")
+		}
+		skipSpace()
+		switch {
+		case i < len(Text) && Text[i] == '"':
+			end := strings.IndexByte(Text[i+1:], '"')
+			if end < 0 {
+				return nil, fmt.Errorf("unterminated value for -%s", attribute.Name)
+			}
+			attribute.Value = Text[i+1 : i+1+end]
+			attribute.Quoted = true
+			i += end + 2
+		case i < len(Text) && !isAttributeStart(Text[i:]):
+			start := i
+			for i < len(Text) && Text[i] != ' ' && Text[i] != '\t' {
+				i++
+			}
+			attribute.Value = Text[start:i]
+		}
+		attributes = append(attributes, attribute)
+	}
+}
+
+// isAttributeStart reports whether the text starts with an attribute name like -Name, as opposed
+// to a negative number like -10.
+func isAttributeStart(Text string) bool {
+	return len(Text) > 1 && Text[0] == '-' && unicode.IsLetter(rune(Text[1]))
+}
@@ -10,7 +10,12 @@ type Client struct {
 	Host     string
 	Username string
 	Password string
+	// Language is the preferred language of localized resources like Elog messages, "en" if empty.
+	// Use SetLanguage to validate it against the languages installed on the controller.
+	Language string
 	Client   *http.Client
+	// jog is the jog session started by PrepareJog.
+	jog jogSession
 }
 
 func NewClient(Host string, Username string, Password string) *Client {
@@ -34,6 +39,14 @@ func (c *Client) GetPassword() string {
 	return c.Password
 }
 
+// GetLanguage returns the preferred language of localized resources.
+func (c *Client) GetLanguage() string {
+	if c.Language == "" {
+		return "en"
+	}
+	return c.Language
+}
+
 func (c *Client) SetHost(Host string) {
 	c.Host = Host
 }
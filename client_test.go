@@ -1,12 +1,27 @@
 package abb
 
 import (
+	"bytes"
+	"context"
 	"encoding/json"
 	"encoding/xml"
+	"errors"
 	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strconv"
+	"strings"
+	"sync"
 	"testing"
+	"time"
 
+	"github.com/atmassey/abb-lib-rws/cfgfile"
 	"github.com/atmassey/abb-lib-rws/structures"
+	"github.com/gorilla/websocket"
 )
 
 func TestControllerActions(t *testing.T) {
@@ -343,3 +358,1456 @@ func TestGetMechUnit(t *testing.T) {
 		fmt.Printf("Title: %s, Mode: %s, Activation Allowed: %s, Drive Module: %s\n", unit, mechUnitsDecoded.Mode[i], mechUnitsDecoded.ActivationAllowed[i], mechUnitsDecoded.DriveModule[i])
 	}
 }
+
+func TestControllerState(t *testing.T) {
+	state := structures.ControllerState{}
+	//sample response from the api documentation
+	state_raw := `{
+    "_links": {
+        "base": {
+            "href": "http://localhost:80/rw/panel/"
+        }
+    },
+    "_embedded": {
+        "_state": [
+            {
+                "_type": "pnl-ctrlstate",
+                "_title": "ctrlstate",
+                "ctrlstate": "motoroff"
+            }
+        ]
+    }
+}`
+	err := json.Unmarshal([]byte(state_raw), &state)
+	if err != nil {
+		t.Error(err)
+	}
+	if state.Embedded.State[0].CtrlState != "motoroff" {
+		t.Errorf("unexpected controller state: %s", state.Embedded.State[0].CtrlState)
+	}
+}
+
+func TestCollisionSubscriptionEvent(t *testing.T) {
+	event := structures.SubscriptionEventXML{}
+	//sample websocket event for a subscription on coldetstate and elog domain 5
+	event_raw := `<?xml version="1.0" encoding="utf-8"?>
+	<html xmlns="http://www.w3.org/1999/xhtml">
+		<head>
+			<base href="http://localhost:80/"/>
+		</head>
+		<body>
+			<div class="state">
+				<a href="http://localhost:80/poll/1" rel="group"></a>
+				<ul>
+					<li class="pnl-coldetstate-ev" title="coldetstate">
+						<a href="/rw/panel/coldetstate" rel="self"></a>
+						<span class="coldetstate">TRIGGERED</span>
+					</li>
+					<li class="elog-message-ev" title="message">
+						<a href="/rw/elog/5/34?lang=en" rel="self"></a>
+					</li>
+				</ul>
+			</div>
+		</body>
+	</html>`
+	err := xml.Unmarshal([]byte(event_raw), &event)
+	if err != nil {
+		t.Error(err)
+	}
+	if len(event.Body.Div.List) != 2 {
+		t.Fatalf("expected 2 events, got %d", len(event.Body.Div.List))
+	}
+	if event.Body.Div.List[0].Span[0].Text != "TRIGGERED" {
+		t.Errorf("unexpected coldetstate: %s", event.Body.Div.List[0].Span[0].Text)
+	}
+	spans := []structures.ElogMessageSpan{
+		{Class: "msgtype", Text: "3"},
+		{Class: "code", Text: "50204"},
+		{Class: "tstamp", Text: "2024-07-16 T 12:00:00"},
+		{Class: "title", Text: "Motion supervision"},
+		{Class: "argc", Text: "2"},
+		{Class: "arg1", Text: "ROB_1"},
+		{Class: "arg2", Text: "3"},
+	}
+	domain, _, ok := elogRef(event.Body.Div.List[1].Link.Href)
+	if !ok || domain != 5 {
+		t.Fatalf("unexpected elog domain: %d", domain)
+	}
+	message := decodeElogMessage(domain, event.Body.Div.List[1].Link.Href, spans)
+	if message.SeqNum != 34 || message.Code != 50204 || message.Timestamp.IsZero() {
+		t.Errorf("unexpected message: %+v", message)
+	}
+	if unit := collisionMechUnit(message, []string{"ROB_1"}); unit != "ROB_1" {
+		t.Errorf("unexpected mechunit: %s", unit)
+	}
+}
+
+// elogPage answers with a page of elog messages, each message is given as its title followed by its spans.
+func elogPage(Messages ...[]string) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		var items strings.Builder
+		for _, message := range Messages {
+			fmt.Fprintf(&items, `<li class="elog-message-li" title="%s">`, message[0])
+			for i := 1; i+1 < len(message); i += 2 {
+				fmt.Fprintf(&items, `<span class="%s">%s</span>`, message[i], message[i+1])
+			}
+			items.WriteString(`</li>`)
+		}
+		fmt.Fprintf(w, `<html><body><div class="state"><ul>%s</ul></div></body></html>`, items.String())
+	}
+}
+
+func TestCollisionHistory(t *testing.T) {
+	collision := func(Ref string, Tstamp string) []string {
+		return []string{Ref, "msgtype", "3", "code", "50204", "tstamp", Tstamp, "title", "Motion supervision", "argc", "1", "arg1", "ROB_1"}
+	}
+	client, fake := newFakeController(t, map[string]http.HandlerFunc{
+		"GET /rw/motionsystem/mechunits": stateJSON(`{"_type":"ms-mechunit-li","_title":"ROB_1"}`),
+		"GET /rw/panel/coldetstate":      stateJSON(`{"_type":"pnl-coldetstate","coldetstate":"TRIGGERED"}`),
+		"GET /rw/elog/5": elogPage(
+			collision("/rw/elog/5/12", "2024-07-16 T 12:00:00"),
+			[]string{"/rw/elog/5/11", "msgtype", "1", "code", "50024", "tstamp", "2024-07-16 T 11:30:00", "title", "Corner path failure"},
+			collision("/rw/elog/5/10", "2024-07-16 T 11:00:00"),
+		),
+		// the newest collision is also logged in the common domain
+		"GET /rw/elog/0": elogPage(collision("/rw/elog/0/80", "2024-07-16 T 12:00:00")),
+	})
+	collisions, err := client.CollisionHistory(0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(collisions) != 2 {
+		t.Fatalf("expected 2 collisions, got %+v (requests %v)", collisions, fake.Requests())
+	}
+	if collisions[0].Code != 50204 || collisions[0].MechUnit != "ROB_1" || collisions[0].RecoveryState != "TRIGGERED" {
+		t.Errorf("unexpected newest collision: %+v", collisions[0])
+	}
+	if !collisions[0].Time.After(collisions[1].Time) || collisions[1].RecoveryState != "" {
+		t.Errorf("unexpected older collision: %+v", collisions[1])
+	}
+}
+
+func TestHandshakeWait(t *testing.T) {
+	handshake := &Handshake{Ack: "Local/DRV_1/diAck", Timeout: 50 * time.Millisecond}
+	events := make(chan map[string]string, 2)
+	// a mismatched value is skipped until the expected value arrives
+	events <- map[string]string{"value": "0"}
+	events <- map[string]string{"value": "1"}
+	if err := handshake.wait(events, "1", HandshakeWaitAck); err != nil {
+		t.Fatal(err)
+	}
+	events <- map[string]string{"value": "1"}
+	err := handshake.wait(events, "0", HandshakeWaitAckDrop)
+	timeoutErr, ok := err.(*HandshakeTimeoutError)
+	if !ok || timeoutErr.Phase != HandshakeWaitAckDrop || timeoutErr.Ack != handshake.Ack {
+		t.Fatalf("expected a timeout while waiting for the ack to drop, got %v", err)
+	}
+	close(events)
+	err = handshake.wait(events, "0", HandshakeWaitAckDrop)
+	if _, isTimeout := err.(*HandshakeTimeoutError); err == nil || isTimeout {
+		t.Errorf("expected an error for a closed subscription, got %v", err)
+	}
+}
+
+func TestParseToolData(t *testing.T) {
+	tool, err := ParseToolData("[TRUE,[[0,0,100],[1,0,0,0]],[1.5,[0,0,1],[1,0,0,0],0,0,0]]")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !tool.RobHold || tool.TFrame.Trans[2] != 100 || tool.TLoad.Mass != 1.5 {
+		t.Errorf("unexpected tooldata: %+v", tool)
+	}
+	issues := ValidateLoadData(tool.TLoad, RobotCapacity("IRB 120-3/0.6"))
+	if len(issues) != 1 || issues[0] != "default inertia" {
+		t.Errorf("unexpected issues: %v", issues)
+	}
+	if _, err := ParseLoadData("[0,[0,0,0]]"); err == nil {
+		t.Error("expected error for truncated loaddata")
+	}
+}
+
+func TestAuditPayloadsUnreadableSymbol(t *testing.T) {
+	client, _ := newFakeController(t, map[string]http.HandlerFunc{
+		"GET /rw/system/robottype": func(w http.ResponseWriter, r *http.Request) {
+			fmt.Fprint(w, `<html><body><div><ul><li title="ROB_1"><span>IRB 120-3/0.6</span></li></ul></div></body></html>`)
+		},
+		"POST /rw/rapid/symbols?action=search-symbols": func(w http.ResponseWriter, r *http.Request) {
+			if err := r.ParseForm(); err != nil {
+				t.Error(err)
+			}
+			symbols := ""
+			if r.PostForm.Get("dattyp") == "tooldata" && r.PostForm.Get("symtyp") == "per" {
+				symbols = `{"_type":"rap-symproppers","name":"gripper","symburl":"RAPID/T_ROB1/user/gripper"},` +
+					`{"_type":"rap-symproppers","name":"broken","symburl":"RAPID/T_ROB1/user/broken"}`
+			}
+			stateJSON(symbols)(w, r)
+		},
+		"GET /rw/rapid/symbol/data/RAPID/T_ROB1/user/gripper": stateJSON(`{"_type":"rap-data","value":"[TRUE,[[0,0,100],[1,0,0,0]],[1.5,[0,0,40],[1,0,0,0],0.01,0.01,0.01]]"}`),
+		"GET /rw/rapid/symbol/data/RAPID/T_ROB1/user/broken":  stateJSON(`{"_type":"rap-data","value":"[TRUE,[[0,0"}`),
+	})
+	audit, err := client.AuditPayloads()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(audit.Tools) != 1 || audit.Tools[0].Name != "gripper" {
+		t.Errorf("unexpected tools: %+v", audit.Tools)
+	}
+	issues := audit.Issues["T_ROB1"]
+	if len(issues) != 1 || issues[0].Name != "broken" || issues[0].DataType != "tooldata" {
+		t.Errorf("unexpected issues: %+v", issues)
+	}
+}
+
+func TestGetSignalJson(t *testing.T) {
+	signal := structures.IOSignalJson{}
+	//sample response for a single analog output
+	data := `{
+    "_links": {
+        "base": {
+            "href": "http://localhost:80/rw/iosystem/"
+        }
+    },
+    "_embedded": {
+        "_state": [
+            {
+                "_type": "ios-signal",
+                "_title": "EtherNetIP/d651/ao_Speed",
+                "name": "ao_Speed",
+                "type": "AO",
+                "category": "",
+                "lvalue": "12.5",
+                "lstate": "not simulated",
+                "quality": "good"
+            }
+        ]
+    }
+}`
+	err := json.Unmarshal([]byte(data), &signal)
+	if err != nil {
+		t.Fatalf("Error decoding response: %s", err)
+	}
+	state := signal.Embedded.State[0]
+	value, err := typedSignalValue(state.Type, state.LValue)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if value.(float64) != 12.5 {
+		t.Errorf("unexpected value: %v", value)
+	}
+	if value, _ := typedSignalValue("DO", "1"); value != true {
+		t.Errorf("unexpected digital value: %v", value)
+	}
+}
+
+func TestSetOutputLimits(t *testing.T) {
+	signalCfg := func(Name string, Attributes string) http.HandlerFunc {
+		return stateJSON(`{"_type":"cfg-dt-instance","_title":"` + Name + `","attrib":[` + Attributes + `]}`)
+	}
+	client, fake := newFakeController(t, map[string]http.HandlerFunc{
+		"GET /rw/cfg/EIO/EIO_SIGNAL/instances/goTest":             signalCfg("goTest", `{"_title":"SignalType","value":"GO"},{"_title":"DeviceMap","value":"0-3"}`),
+		"GET /rw/cfg/EIO/EIO_SIGNAL/instances/aoTest":             signalCfg("aoTest", `{"_title":"SignalType","value":"AO"}`),
+		"GET /rw/iosystem/signals/Local/DRV_1/goTest":             stateJSON(`{"_type":"ios-signal","name":"goTest","type":"GO","lvalue":"0"}`),
+		"GET /rw/iosystem/signals/Local/DRV_1/aoTest":             stateJSON(`{"_type":"ios-signal","name":"aoTest","type":"AO","lvalue":"0"}`),
+		"POST /rw/iosystem/signals/Local/DRV_1/goTest?action=set": status(http.StatusNoContent),
+		"POST /rw/iosystem/signals/Local/DRV_1/aoTest?action=set": status(http.StatusNoContent),
+	})
+	if err := client.SetGroupOutput("Local/DRV_1/goTest", 16); err == nil {
+		t.Error("expected an error for a value that does not fit in 4 bits")
+	}
+	if err := client.SetGroupOutput("Local/DRV_1/goTest", 15); err != nil {
+		t.Error(err)
+	}
+	if err := client.SetAnalogOutput("Local/DRV_1/aoTest", 5.5); err != nil {
+		t.Errorf("unexpected error without configured limits: %s (requests %v)", err, fake.Requests())
+	}
+}
+
+func TestSimulateSignal(t *testing.T) {
+	var posted []url.Values
+	client, _ := newFakeController(t, map[string]http.HandlerFunc{
+		"POST /rw/iosystem/signals/Local/DRV_1/diTest?action=set": func(w http.ResponseWriter, r *http.Request) {
+			if err := r.ParseForm(); err != nil {
+				t.Error(err)
+			}
+			posted = append(posted, r.PostForm)
+			w.WriteHeader(http.StatusNoContent)
+		},
+		"GET /rw/iosystem/signals": stateJSON(`{"_type":"ios-signal-li","_title":"Local/DRV_1/diTest","name":"diTest","type":"DI","lvalue":"1","lstate":"simulated"},` +
+			`{"_type":"ios-signal-li","_title":"Local/DRV_1/diTest2","name":"diTest2","type":"DI","lvalue":"0","lstate":"not simulated"}`),
+	})
+	if err := client.SimulateSignal("Local/DRV_1/diTest", "1"); err == nil {
+		t.Error("expected an error for an unsupported value type")
+	}
+	if err := client.SimulateSignal("Local/DRV_1/diTest", true); err != nil {
+		t.Fatal(err)
+	}
+	if err := client.UnsimulateSignal("Local/DRV_1/diTest"); err != nil {
+		t.Fatal(err)
+	}
+	if len(posted) != 2 || posted[0].Get("lstate") != "simulated" || posted[0].Get("lvalue") != "1" || posted[1].Get("lstate") != "not simulated" {
+		t.Errorf("unexpected requests: %v", posted)
+	}
+	simulated, err := client.ListSimulatedSignals()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(simulated) != 1 || simulated[0].Path != "Local/DRV_1/diTest" || simulated[0].Value != true {
+		t.Errorf("unexpected simulated signals: %+v", simulated)
+	}
+}
+
+func TestDeviceTree(t *testing.T) {
+	signal := func(Path string) string {
+		return `{"_type":"ios-signal-li","_title":"` + Path + `","type":"DI","lvalue":"0"}`
+	}
+	client, _ := newFakeController(t, map[string]http.HandlerFunc{
+		"GET /rw/iosystem/networks": stateJSON(`{"_type":"ios-network-li","_title":"Local","name":"Local","lstate":"started"}`),
+		"GET /rw/iosystem/devices": func(w http.ResponseWriter, r *http.Request) {
+			if r.URL.Query().Get("network") != "Local" {
+				t.Errorf("unexpected network: %s", r.URL.Query().Get("network"))
+			}
+			stateJSON(`{"_type":"ios-device-li","_title":"Local/DRV_1","name":"DRV_1","lstate":"enabled","address":"-"}`)(w, r)
+		},
+		"GET /rw/iosystem/signals": stateJSON(signal("Local/PANEL/diZ") + "," + signal("Local/DRV_1/diTest") + "," +
+			signal("Local/PANEL/diA") + "," + signal("Local/DRV_2/diB")),
+	})
+	tree, err := client.DeviceTree()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(tree.Networks) != 1 || len(tree.Networks[0].Devices) != 1 {
+		t.Fatalf("unexpected tree: %+v", tree)
+	}
+	device := tree.Networks[0].Devices[0]
+	if !device.Device.Enabled || len(device.Signals) != 1 || device.Signals[0].Path != "Local/DRV_1/diTest" {
+		t.Errorf("unexpected device: %+v", device)
+	}
+	var unmapped []string
+	for _, signal := range tree.Unmapped {
+		unmapped = append(unmapped, signal.Path)
+	}
+	if strings.Join(unmapped, ",") != "Local/DRV_2/diB,Local/PANEL/diA,Local/PANEL/diZ" {
+		t.Errorf("unexpected unmapped signals: %v", unmapped)
+	}
+}
+
+func TestListSignalsPaging(t *testing.T) {
+	client, _ := newFakeController(t, map[string]http.HandlerFunc{
+		"GET /rw/iosystem/signals": func(w http.ResponseWriter, r *http.Request) {
+			if r.URL.Query().Get("start") == "0" {
+				fmt.Fprint(w, `{"_links":{"next":{"href":"signals?type=GI&start=1&limit=1&json=1"}},"_embedded":{"_state":[`+
+					`{"_type":"ios-signal-li","_title":"Local/DRV_1/giTest","type":"GI","lvalue":"3"}]}}`)
+				return
+			}
+			stateJSON(`{"_type":"ios-signal-li","_title":"Local/DRV_1/giBroken","type":"GI","lvalue":"-1"},`+
+				`{"_type":"ios-signal-li","_title":"Local/DRV_1/giEmpty","type":"GI","lvalue":""}`)(w, r)
+		},
+		"GET /rw/iosystem/signals/Local/DRV_1/giEmpty": stateJSON(`{"_type":"ios-signal","name":"giEmpty","type":"GI","lvalue":""}`),
+	})
+	query := url.Values{"type": {"GI"}}
+	signals, err := client.listSignals(query)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(query) != 1 {
+		t.Errorf("caller query was modified: %v", query)
+	}
+	if len(signals) != 3 || signals[0].Value != uint64(3) {
+		t.Fatalf("unexpected signals: %+v", signals)
+	}
+	if signals[1].Value != nil || signals[1].LValue != "-1" {
+		t.Errorf("expected an untyped value: %+v", signals[1])
+	}
+	if signals[2].Value != nil || signals[2].LValue != "" {
+		t.Errorf("expected an untyped empty value: %+v", signals[2])
+	}
+	signal, err := client.GetSignal("Local/DRV_1/giEmpty")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if signal.Value != nil || signal.LValue != "" {
+		t.Errorf("expected an untyped empty value: %+v", signal)
+	}
+}
+
+func TestSignalFilter(t *testing.T) {
+	signal := structures.Signal{Path: "PROFINET/SLAVE_PLC/soRBT_Safety_OK", Name: "soRBT_Safety_OK", Type: "DO", Category: "ProfiSafe"}
+	if !signalMatches(signal, structures.SignalFilter{Network: "PROFINET", Device: "SLAVE_PLC", Type: "DO"}) {
+		t.Error("expected signal to match filter")
+	}
+	if signalMatches(signal, structures.SignalFilter{Category: "Safety"}) {
+		t.Error("expected signal not to match category")
+	}
+	if signalMatches(structures.Signal{Path: "diVirtual", Name: "diVirtual"}, structures.SignalFilter{Network: "PROFINET"}) {
+		t.Error("expected signal without device not to match network")
+	}
+}
+
+func TestCrossConnectionGraph(t *testing.T) {
+	connection := crossConnectionFromAttributes(map[string]string{
+		"Name":        "cross_1",
+		"Res":         "doResult",
+		"Act1":        "di1",
+		"Oper1":       "AND",
+		"Act2":        "di2",
+		"Act2_invert": "true",
+		"Oper2":       "OR",
+	})
+	if len(connection.Actors) != 2 || !connection.Actors[1].Invert || connection.Actors[1].Operator != "" {
+		t.Fatalf("unexpected cross connection: %+v", connection)
+	}
+	graph := structures.CrossConnectionGraph{Connections: []structures.CrossConnection{connection}}
+	var dot bytes.Buffer
+	if err := WriteCrossConnectionDOT(&dot, &graph); err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(dot.String(), `label="di1 AND !di2"`) {
+		t.Errorf("unexpected DOT output: %s", dot.String())
+	}
+	if deviceMapLength("0-3,8-11") != 8 {
+		t.Errorf("unexpected device map length: %d", deviceMapLength("0-3,8-11"))
+	}
+}
+
+func TestDiffIOSnapshots(t *testing.T) {
+	a := structures.IOSnapshot{
+		Signals: []structures.SignalSnapshot{
+			{Path: "Local/DRV_1/do1", Type: "DO", LValue: "0"},
+			{Path: "Local/DRV_1/di1", Type: "DI", LValue: "1"},
+			{Path: "Local/DRV_1/removed", Type: "DI", LValue: "0"},
+		},
+		Devices: []structures.DeviceSnapshot{{Path: "Local/DRV_1", LState: "enabled", PState: "running"}},
+	}
+	b := structures.IOSnapshot{
+		Signals: []structures.SignalSnapshot{
+			{Path: "Local/DRV_1/do1", Type: "DO", LValue: "1"},
+			{Path: "Local/DRV_1/di1", Type: "DI", LValue: "1", Simulated: true},
+		},
+		Devices: []structures.DeviceSnapshot{{Path: "Local/DRV_1", LState: "enabled", PState: "running"}},
+	}
+	diff := DiffIOSnapshots(&a, &b)
+	if len(diff.Signals) != 3 || len(diff.Devices) != 0 {
+		t.Fatalf("unexpected diff: %+v", diff)
+	}
+	if diff.Signals[0].Path != "Local/DRV_1/di1" || !diff.Signals[0].SimulatedAfter {
+		t.Errorf("unexpected change: %+v", diff.Signals[0])
+	}
+	if diff.Signals[2].Path != "Local/DRV_1/removed" || diff.Signals[2].After != "" {
+		t.Errorf("unexpected change: %+v", diff.Signals[2])
+	}
+}
+
+func TestIOTestPlan(t *testing.T) {
+	plan_raw := `{
+    "name": "cell 1 gripper",
+    "steps": [
+        {
+            "name": "close gripper",
+            "signal": "Local/DRV_1/doGripClose",
+            "value": "1",
+            "expect": [{"signal": "Local/DRV_1/diGripClosed", "value": "1"}],
+            "timeout_ms": 500
+        }
+    ]
+}`
+	plan, err := ParseIOTestPlan(strings.NewReader(plan_raw))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(plan.Steps) != 1 || plan.Steps[0].Expect[0].Signal != "Local/DRV_1/diGripClosed" {
+		t.Fatalf("unexpected plan: %+v", plan)
+	}
+	plan_yaml := `name: cell 1 gripper
+steps:
+  - name: close gripper
+    signal: Local/DRV_1/doGripClose
+    value: 1
+    expect:
+      - signal: Local/DRV_1/diGripClosed
+        value: 1
+    timeout_ms: 500
+  - name: part present
+    signal: Local/DRV_1/diPartPresent
+    value: 1
+    simulate: true
+`
+	path := filepath.Join(t.TempDir(), "gripper.yaml")
+	if err := os.WriteFile(path, []byte(plan_yaml), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	yamlPlan, err := LoadIOTestPlan(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(yamlPlan.Steps[0], plan.Steps[0]) || len(yamlPlan.Steps) != 2 || !yamlPlan.Steps[1].Simulate {
+		t.Errorf("unexpected YAML plan: %+v", yamlPlan)
+	}
+	if _, err := ParseIOTestPlanYAML(strings.NewReader("name: x\nsteps:\n  - signal: diA\n    valu: 1\n")); err == nil {
+		t.Error("expected an error for an unknown YAML key")
+	}
+	report := structures.IOTestReport{Plan: plan.Name, Steps: []structures.IOTestStepResult{{
+		Name:   "close gripper",
+		Signal: "Local/DRV_1/doGripClose",
+		Value:  "1",
+		Expectations: []structures.IOTestExpectationResult{
+			{Signal: "Local/DRV_1/diGripClosed", Expected: "1", Actual: "0"},
+		},
+	}}}
+	var markdown bytes.Buffer
+	if err := WriteIOTestReportMarkdown(&markdown, &report); err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(markdown.String(), "diGripClosed expected 1 got 0") {
+		t.Errorf("unexpected report: %s", markdown.String())
+	}
+	if !signalValuesEqual("AO", "12.50", "12.5") {
+		t.Error("expected analog values to be equal")
+	}
+}
+
+func TestRunIOTestPlanRestoresSimulation(t *testing.T) {
+	var mu sync.Mutex
+	// lstate and lvalue per signal, diPreset is already simulated before the test
+	signals := map[string][2]string{
+		"diPreset": {"simulated", "1"},
+		"diFree":   {"not simulated", "0"},
+	}
+	var posts []string
+	routes := map[string]http.HandlerFunc{}
+	for name := range signals {
+		name := name
+		routes["GET /rw/iosystem/signals/Local/DRV_1/"+name] = func(w http.ResponseWriter, r *http.Request) {
+			mu.Lock()
+			state := signals[name]
+			mu.Unlock()
+			stateJSON(`{"_type":"ios-signal","name":"`+name+`","type":"DI","lstate":"`+state[0]+`","lvalue":"`+state[1]+`"}`)(w, r)
+		}
+		routes["POST /rw/iosystem/signals/Local/DRV_1/"+name+"?action=set"] = func(w http.ResponseWriter, r *http.Request) {
+			if err := r.ParseForm(); err != nil {
+				t.Error(err)
+			}
+			mu.Lock()
+			defer mu.Unlock()
+			posts = append(posts, name+" "+r.PostForm.Encode())
+			if name == "diFree" {
+				w.WriteHeader(http.StatusForbidden)
+				return
+			}
+			signals[name] = [2]string{r.PostForm.Get("lstate"), r.PostForm.Get("lvalue")}
+			w.WriteHeader(http.StatusNoContent)
+		}
+	}
+	routes["DELETE /rw/iosystem/signals?action=unblock-signal"] = func(w http.ResponseWriter, r *http.Request) {
+		t.Error("signals must only be unblocked when a restore fails")
+	}
+	client, _ := newFakeController(t, routes)
+	report, err := client.RunIOTestPlan(structures.IOTestPlan{Name: "restore", Steps: []structures.IOTestStep{
+		{Name: "preset", Signal: "Local/DRV_1/diPreset", Value: "0", Simulate: true, TimeoutMs: 1},
+		{Name: "free", Signal: "Local/DRV_1/diFree", Value: "1", Simulate: true, TimeoutMs: 1},
+	}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if report.Steps[1].Error == "" || report.CleanupError != "" {
+		t.Errorf("unexpected report: %+v", report)
+	}
+	mu.Lock()
+	defer mu.Unlock()
+	if signals["diPreset"] != [2]string{"simulated", "1"} {
+		t.Errorf("simulation state of diPreset not restored: %v", signals["diPreset"])
+	}
+	// the failed simulation of diFree must not be undone
+	if len(posts) != 3 || !strings.HasPrefix(posts[2], "diPreset ") {
+		t.Errorf("unexpected requests: %v", posts)
+	}
+}
+
+func TestRunIOTestPlanUnblocksOnRestoreFailure(t *testing.T) {
+	var mu sync.Mutex
+	posts := 0
+	unblocks := 0
+	client, _ := newFakeController(t, map[string]http.HandlerFunc{
+		"GET /rw/iosystem/signals/Local/DRV_1/diStuck": stateJSON(`{"_type":"ios-signal","name":"diStuck","type":"DI","lstate":"not simulated","lvalue":"0"}`),
+		"POST /rw/iosystem/signals/Local/DRV_1/diStuck?action=set": func(w http.ResponseWriter, r *http.Request) {
+			mu.Lock()
+			defer mu.Unlock()
+			posts++
+			// simulating works, removing the simulation afterwards doesn't
+			if posts > 1 {
+				w.WriteHeader(http.StatusForbidden)
+				return
+			}
+			w.WriteHeader(http.StatusNoContent)
+		},
+		"DELETE /rw/iosystem/signals?action=unblock-signal": func(w http.ResponseWriter, r *http.Request) {
+			mu.Lock()
+			defer mu.Unlock()
+			unblocks++
+			w.WriteHeader(http.StatusInternalServerError)
+		},
+	})
+	report, err := client.RunIOTestPlan(structures.IOTestPlan{Name: "stuck", Steps: []structures.IOTestStep{
+		{Name: "stuck", Signal: "Local/DRV_1/diStuck", Value: "1", Simulate: true, TimeoutMs: 1},
+	}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	mu.Lock()
+	defer mu.Unlock()
+	if unblocks != 1 {
+		t.Errorf("expected one unblock fallback, got %d", unblocks)
+	}
+	if !strings.Contains(report.CleanupError, "Local/DRV_1/diStuck: HTTP Status Code: 403") || !strings.Contains(report.CleanupError, "unable to unblock signals: HTTP Status Code: 500") {
+		t.Errorf("unexpected cleanup error: %s", report.CleanupError)
+	}
+}
+
+func TestProfinetAlarmsJson(t *testing.T) {
+	alarms := structures.ProfinetAlarmsJson{}
+	//sample response for the alarms of a profinet device
+	data := `{
+    "_links": {
+        "base": {
+            "href": "http://localhost:80/rw/iosystem/devices/PROFINET/PN_Device/"
+        }
+    },
+    "_embedded": {
+        "_state": [
+            {
+                "_type": "ios-pnalarm-li",
+                "_title": "alarm1",
+                "alarm-type": "Diagnosis",
+                "slot": "1",
+                "subslot": "0x8001",
+                "channel": "32768",
+                "channel-error-type": "0x0001",
+                "ext-channel-error-type": "",
+                "severity": "fault",
+                "time": "2024-07-16 T 12:00:00",
+                "text": "Short circuit"
+            },
+            {
+                "_type": "ios-pnalarm-li",
+                "_title": "alarm2",
+                "slot": "one"
+            }
+        ]
+    }
+}`
+	err := json.Unmarshal([]byte(data), &alarms)
+	if err != nil {
+		t.Fatalf("Error decoding response: %s", err)
+	}
+	alarm, err := profinetAlarmFromJson(alarms.Embedded.State[0])
+	if err != nil {
+		t.Fatal(err)
+	}
+	if alarm.Slot != 1 || alarm.Subslot != 0x8001 || alarm.Channel != 32768 || alarm.ChannelErrorType != 1 || alarm.Time.IsZero() {
+		t.Errorf("unexpected alarm: %+v", alarm)
+	}
+	if _, err := profinetAlarmFromJson(alarms.Embedded.State[1]); err == nil {
+		t.Error("expected an error for an invalid slot")
+	}
+	padded, err := profinetAlarmFromJson(structures.ProfinetAlarmsJsonMeta{Slot: "08", Channel: "010", Subslot: "0X10"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if padded.Slot != 8 || padded.Channel != 10 || padded.Subslot != 16 || !padded.Time.IsZero() {
+		t.Errorf("unexpected alarm: %+v", padded)
+	}
+	if _, err := profinetAlarmFromJson(structures.ProfinetAlarmsJsonMeta{Time: "yesterday"}); err == nil {
+		t.Error("expected an error for an invalid time")
+	}
+}
+
+func TestCfgInstancesJson(t *testing.T) {
+	instances := structures.CfgInstancesJson{}
+	//sample response for a page of EIO_SIGNAL instances
+	data := `{
+    "_links": {
+        "base": {
+            "href": "http://localhost:80/rw/cfg/EIO/EIO_SIGNAL/"
+        },
+        "next": {
+            "href": "instances?start=1&limit=1&json=1"
+        }
+    },
+    "_embedded": {
+        "_state": [
+            {
+                "_type": "cfg-dt-instance-li",
+                "_title": "doTest1",
+                "attrib": [
+                    {"_type": "cfg-ia-t", "_title": "Name", "value": "doTest1"},
+                    {"_type": "cfg-ia-t", "_title": "SignalType", "value": "DO"},
+                    {"_type": "cfg-ia-t", "_title": "Device", "value": "DRV_1"}
+                ]
+            }
+        ]
+    }
+}`
+	err := json.Unmarshal([]byte(data), &instances)
+	if err != nil {
+		t.Fatalf("Error decoding response: %s", err)
+	}
+	instance := cfgInstanceFromJson("EIO", "EIO_SIGNAL", instances.Embedded.State[0])
+	if instance.Name != "doTest1" || instance.Attributes["SignalType"] != "DO" {
+		t.Errorf("unexpected instance: %+v", instance)
+	}
+	next := nextPageURL("http://localhost/rw/cfg/EIO/EIO_SIGNAL/instances?json=1", instances.Links.Base.Href, instances.Links.Next.Href)
+	if next != "http://localhost:80/rw/cfg/EIO/EIO_SIGNAL/instances?start=1&limit=1&json=1" {
+		t.Errorf("unexpected next page: %s", next)
+	}
+	// without a base link the href is relative to the request
+	next = nextPageURL("http://localhost/rw/cfg/EIO/EIO_SIGNAL/instances?json=1", "", instances.Links.Next.Href)
+	if next != "http://localhost/rw/cfg/EIO/EIO_SIGNAL/instances?start=1&limit=1&json=1" {
+		t.Errorf("unexpected next page without base: %s", next)
+	}
+}
+
+func TestCfgInstanceMismatch(t *testing.T) {
+	file, err := cfgfile.Parse(strings.NewReader("EIO:CFG_1.0:6:1::\nEIO_SIGNAL:\n\n      -Name \"ao1\" -SignalType \"AO\" -MaxLog 10 -Invert\n"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	instance := file.Type("EIO_SIGNAL").Instances[0]
+	attributes := structures.CfgAttributes{"Name": "ao1", "SignalType": "AO", "MaxLog": "10.0", "Invert": "true"}
+	if reason := cfgInstanceMismatch(instance, attributes); reason != "" {
+		t.Errorf("unexpected mismatch: %s", reason)
+	}
+	attributes["SignalType"] = "AI"
+	if reason := cfgInstanceMismatch(instance, attributes); reason == "" {
+		t.Error("expected a mismatch for the changed signal type")
+	}
+	if reason := cfgInstanceMismatch(instance, nil); reason == "" {
+		t.Error("expected a mismatch for a missing instance")
+	}
+}
+
+func TestLoadCfgFileAddMode(t *testing.T) {
+	path := t.TempDir() + "/EIO.cfg"
+	data := "EIO:CFG_1.0:6:1::\nEIO_SIGNAL:\n\n      -Name \"doOld\" -SignalType \"DO\" -Device \"DRV_2\"\n\n      -Name \"doNew\" -SignalType \"DO\"\n"
+	if err := os.WriteFile(path, []byte(data), 0644); err != nil {
+		t.Fatal(err)
+	}
+	instance := func(Name string, Device string) string {
+		return `{"_type":"cfg-dt-instance-li","_title":"` + Name + `","attrib":[{"_title":"Name","value":"` + Name +
+			`"},{"_title":"SignalType","value":"DO"},{"_title":"Device","value":"` + Device + `"}]}`
+	}
+	var loaded bool
+	client, _ := newFakeController(t, map[string]http.HandlerFunc{
+		"GET /rw/cfg/EIO/EIO_SIGNAL/instances": func(w http.ResponseWriter, r *http.Request) {
+			// doOld exists with a different device and is kept unchanged by the add mode
+			instances := instance("doOld", "DRV_1")
+			if loaded {
+				instances += "," + instance("doNew", "")
+			}
+			stateJSON(instances)(w, r)
+		},
+		"PUT /fileservice/$TEMP/EIO.cfg": status(http.StatusCreated),
+		"POST /rw/cfg?action=load": func(w http.ResponseWriter, r *http.Request) {
+			loaded = true
+			w.WriteHeader(http.StatusNoContent)
+		},
+		"DELETE /fileservice/$TEMP/EIO.cfg": status(http.StatusNoContent),
+	})
+	result, err := client.LoadCfgFile(path, CfgLoadAdd)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if result.Accepted != 1 || result.Skipped != 1 || len(result.Rejected) != 0 || !result.RestartRequired {
+		t.Errorf("unexpected result: %+v", result)
+	}
+}
+
+func TestDiffConfig(t *testing.T) {
+	parse := func(data string) map[string]*cfgfile.File {
+		file, err := cfgfile.Parse(strings.NewReader(data))
+		if err != nil {
+			t.Fatal(err)
+		}
+		return map[string]*cfgfile.File{file.Domain: file}
+	}
+	a := parse(`MOC:CFG_1.0:6:0::
+# cell 1
+MOTOR_CALIB:
+
+      -name "rob1_1" -cal_offset 1.2345 -cal_offset_valid
+ARM:
+
+      -name "rob1_1" -upper_joint_bound 2.87979 -lower_joint_bound -2.87979
+`)
+	b := parse(`MOC:CFG_1.0:7:0::
+MOTOR_CALIB:
+
+      -name "rob1_1" -cal_offset 2.5 -cal_offset_valid
+ARM:
+
+      -name "rob1_1" -upper_joint_bound 2.5 -lower_joint_bound -2.87979
+
+      -name "rob1_2" -upper_joint_bound 1
+`)
+	diff := structures.ConfigDiff{A: "cell1", B: "cell2", Changes: diffCfgDomains(a, b)}
+	if len(diff.Changes) != 2 {
+		t.Fatalf("unexpected changes: %+v", diff.Changes)
+	}
+	changed := diff.Changes[0]
+	if changed.Kind != structures.CfgChanged || changed.Attribute != "upper_joint_bound" || changed.B != "2.5" {
+		t.Errorf("unexpected change: %+v", changed)
+	}
+	if diff.Changes[1].Kind != structures.CfgAdded || diff.Changes[1].Instance != "rob1_2" {
+		t.Errorf("unexpected change: %+v", diff.Changes[1])
+	}
+	var report bytes.Buffer
+	if err := WriteConfigDiffReport(&report, &diff); err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(report.String(), `~ rob1_1 -upper_joint_bound: "2.87979" -> "2.5"`) {
+		t.Errorf("unexpected report: %s", report.String())
+	}
+	if len(diffCfgDomains(a, a)) != 0 {
+		t.Error("expected no changes for identical configurations")
+	}
+}
+
+func TestDiffConfigUnnamedAndDuplicates(t *testing.T) {
+	parse := func(data string) map[string]*cfgfile.File {
+		file, err := cfgfile.Parse(strings.NewReader(data))
+		if err != nil {
+			t.Fatal(err)
+		}
+		return map[string]*cfgfile.File{file.Domain: file}
+	}
+	a := parse(`SYS:CFG_1.0:6:0::
+CAB_REGAIN_DIST:
+
+      -Tcp_dist 0.05 -Tcp_rot 0.2
+
+      -Tcp_dist 0.5 -Tcp_rot 1.57 -Ext_dist 0.05
+CAB_TASKS:
+
+      -Name "T_ROB1" -Type "NORMAL"
+
+      -Name "T_ROB1" -Type "STATIC"
+`)
+	// the unnamed instances are reordered, which is not a change
+	b := parse(`SYS:CFG_1.0:6:0::
+CAB_REGAIN_DIST:
+
+      -Tcp_dist 0.5 -Tcp_rot 1.57 -Ext_dist 0.05
+
+      -Tcp_dist 0.05 -Tcp_rot 0.2
+CAB_TASKS:
+
+      -Name "T_ROB1" -Type "STATIC"
+`)
+	changes := diffCfgDomains(a, b)
+	if len(changes) != 1 {
+		t.Fatalf("unexpected changes: %+v", changes)
+	}
+	if changes[0].Kind != structures.CfgDuplicate || changes[0].Instance != "T_ROB1" || changes[0].A != "2" || changes[0].B != "0" {
+		t.Errorf("unexpected change: %+v", changes[0])
+	}
+	b = parse(`SYS:CFG_1.0:6:0::
+CAB_REGAIN_DIST:
+
+      -Tcp_dist 0.05 -Tcp_rot 0.3
+
+      -Tcp_dist 0.5 -Tcp_rot 1.57 -Ext_dist 0.05
+`)
+	var kinds []string
+	for _, change := range diffCfgDomains(a, b) {
+		if change.Type == "CAB_REGAIN_DIST" {
+			kinds = append(kinds, change.Kind+" "+change.Instance)
+		}
+	}
+	if strings.Join(kinds, ", ") != `removed #{-Tcp_dist "0.05" -Tcp_rot "0.2"}, added #{-Tcp_dist "0.05" -Tcp_rot "0.3"}` {
+		t.Errorf("unexpected unnamed changes: %v", kinds)
+	}
+}
+
+func TestElogDomainMessages(t *testing.T) {
+	page := structures.ElogDomainMessagesXML{}
+	//sample response for a page of elog domain 1
+	page_raw := `<?xml version="1.0" encoding="utf-8"?>
+	<html xmlns="http://www.w3.org/1999/xhtml">
+		<head>
+			<base href="http://localhost:80/rw/elog/1/"/>
+		</head>
+		<body>
+			<div class="state">
+				<a href="" rel="self"></a>
+				<a href="?lang=en&amp;start=2&amp;limit=2" rel="next"></a>
+				<ul>
+					<li class="elog-message-li" title="/rw/elog/1/41">
+						<span class="msgtype">3</span>
+						<span class="code">50204</span>
+						<span class="tstamp">2024-07-16 T 12:00:00</span>
+						<span class="title">Motion supervision</span>
+					</li>
+					<li class="elog-message-li" title="/rw/elog/1/40">
+						<span class="msgtype">1</span>
+						<span class="code">10010</span>
+						<span class="tstamp">2024-07-16 T 11:00:00</span>
+						<span class="title">Motors OFF state</span>
+					</li>
+				</ul>
+			</div>
+		</body>
+	</html>`
+	err := xml.Unmarshal([]byte(page_raw), &page)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(page.Body.Div.List) != 2 || page.Body.Div.Links[1].Rel != "next" {
+		t.Fatalf("unexpected page: %+v", page.Body.Div)
+	}
+	next := nextPageURL("http://localhost/rw/elog/1?lang=en", page.Head.BaseLink.Href, page.Body.Div.Links[1].Href)
+	if next != "http://localhost:80/rw/elog/1/?lang=en&start=2&limit=2" {
+		t.Errorf("unexpected next page: %s", next)
+	}
+	filter := structures.ElogFilter{
+		Types: []int{structures.ElogError},
+		Codes: []structures.ElogCodeRange{{Min: 50000, Max: 50999}},
+	}
+	var matched []structures.ElogMessage
+	for _, li := range page.Body.Div.List {
+		message := decodeElogMessage(1, li.Title, li.Span)
+		if elogMessageMatches(message, filter) {
+			matched = append(matched, message)
+		}
+	}
+	if len(matched) != 1 || matched[0].SeqNum != 41 {
+		t.Errorf("unexpected messages: %+v", matched)
+	}
+}
+
+func TestControllerLanguages(t *testing.T) {
+	languages := structures.ControllerLanguagesJson{}
+	data := `{"_links":{"base":{"href":"http://localhost:80/rw/system/"}},"_embedded":{"_state":[
+		{"_type":"sys-language-li","_title":"en","lang":"en"},
+		{"_type":"sys-language-li","_title":"de","lang":"de"}]}}`
+	err := json.Unmarshal([]byte(data), &languages)
+	if err != nil {
+		t.Fatalf("Error decoding response: %s", err)
+	}
+	if len(languages.Embedded.State) != 2 || languages.Embedded.State[1].Lang != "de" {
+		t.Errorf("unexpected languages: %+v", languages)
+	}
+	client := NewClient("localhost", "Default User", "robotics")
+	if client.GetLanguage() != "en" {
+		t.Errorf("unexpected default language: %s", client.GetLanguage())
+	}
+}
+
+func TestElogStreamDeduplication(t *testing.T) {
+	domain, seqNum, ok := elogRef("/rw/elog/1/34?lang=en")
+	if !ok || domain != 1 || seqNum != 34 {
+		t.Errorf("unexpected elog ref: %d %d %v", domain, seqNum, ok)
+	}
+	stream := elogStream{last: map[int]structures.ElogPosition{1: {SeqNum: 34}}, messages: make(chan structures.ElogMessage, 3)}
+	ctx := context.Background()
+	for _, seq := range []int{33, 35, 35, 34, 36} {
+		stream.deliver(ctx, structures.ElogMessage{Domain: 1, SeqNum: seq})
+	}
+	close(stream.messages)
+	var delivered []int
+	for message := range stream.messages {
+		delivered = append(delivered, message.SeqNum)
+	}
+	if fmt.Sprint(delivered) != "[35 36]" {
+		t.Errorf("unexpected delivered messages: %v", delivered)
+	}
+	message := structures.ElogMessage{Domain: 1, SeqNum: 35, MsgType: 3, Code: 50204, Title: "Motion supervision", Args: []string{"ROB_1", "3"}}
+	decoded := DecodeElogEvent(elogEvent(message))
+	if decoded.SeqNum != 35 || decoded.Code != 50204 || fmt.Sprint(decoded.Args) != "[ROB_1 3]" {
+		t.Errorf("unexpected decoded event: %+v", decoded)
+	}
+}
+
+func TestElogStreamReconnectBackfill(t *testing.T) {
+	message := func(SeqNum int) []string {
+		return []string{"/rw/elog/1/" + strconv.Itoa(SeqNum), "msgtype", "1", "code", "10011", "tstamp", "2024-07-16 T 12:00:0" + strconv.Itoa(SeqNum-10), "title", "Motors ON state"}
+	}
+	var mu sync.Mutex
+	connections := 0
+	upgrader := websocket.Upgrader{}
+	client, fake := newFakeController(t, map[string]http.HandlerFunc{
+		"POST /subscription": func(w http.ResponseWriter, r *http.Request) {
+			w.Header().Set("Location", "ws://"+r.Host+"/poll/1")
+			w.WriteHeader(http.StatusCreated)
+		},
+		"GET /poll/1": func(w http.ResponseWriter, r *http.Request) {
+			conn, err := upgrader.Upgrade(w, r, nil)
+			if err != nil {
+				t.Error(err)
+				return
+			}
+			defer conn.Close()
+			mu.Lock()
+			connections++
+			first := connections == 1
+			mu.Unlock()
+			if first {
+				event := `<html><body><div class="state"><ul>` +
+					`<li class="elog-message-ev"><a href="/rw/elog/1/11?lang=en" rel="self"></a></li>` +
+					`<li class="elog-message-ev"><a href="/rw/elog/1/12?lang=en" rel="self"></a></li>` +
+					`</ul></div></body></html>`
+				if err := conn.WriteMessage(websocket.TextMessage, []byte(event)); err != nil {
+					t.Error(err)
+				}
+			}
+			// hold the connection until the client closes it
+			for {
+				if _, _, err := conn.ReadMessage(); err != nil {
+					return
+				}
+			}
+		},
+		// message 11 can't be fetched from the event, it is only available through the history
+		"GET /rw/elog/1/11": status(http.StatusServiceUnavailable),
+		"GET /rw/elog/1/12": elogPage(message(12)),
+		// the history has the messages only after the event was sent on the first connection
+		"GET /rw/elog/1": func(w http.ResponseWriter, r *http.Request) {
+			mu.Lock()
+			reconnected := connections > 1
+			mu.Unlock()
+			if reconnected {
+				elogPage(message(12), message(11))(w, r)
+				return
+			}
+			elogPage()(w, r)
+		},
+	})
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+	messages, err := client.SubscribeToElogMessages(ctx, map[int]structures.ElogPosition{1: {SeqNum: 10}}, 1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var delivered []int
+	for len(delivered) < 2 {
+		select {
+		case message := <-messages:
+			delivered = append(delivered, message.SeqNum)
+		case <-ctx.Done():
+			t.Fatalf("timed out after %v (requests %v)", delivered, fake.Requests())
+		}
+	}
+	if fmt.Sprint(delivered) != "[11 12]" {
+		t.Errorf("unexpected delivered messages: %v", delivered)
+	}
+	for _, request := range fake.Requests() {
+		if request == "GET /rw/elog/1/12" {
+			t.Error("message 12 was fetched past the gap at message 11")
+		}
+	}
+	mu.Lock()
+	defer mu.Unlock()
+	if connections != 2 {
+		t.Errorf("expected a reconnect, got %d connections", connections)
+	}
+}
+
+func TestElogStreamAfterClear(t *testing.T) {
+	// the Elog was cleared after message 50 was processed, the new messages restart at 1
+	message := func(SeqNum int) []string {
+		return []string{"/rw/elog/1/" + strconv.Itoa(SeqNum), "msgtype", "1", "code", "10011", "tstamp", "2024-07-16 T 13:00:0" + strconv.Itoa(SeqNum), "title", "Motors ON state"}
+	}
+	upgrader := websocket.Upgrader{}
+	client, fake := newFakeController(t, map[string]http.HandlerFunc{
+		"POST /subscription": func(w http.ResponseWriter, r *http.Request) {
+			w.Header().Set("Location", "ws://"+r.Host+"/poll/1")
+			w.WriteHeader(http.StatusCreated)
+		},
+		"GET /poll/1": func(w http.ResponseWriter, r *http.Request) {
+			conn, err := upgrader.Upgrade(w, r, nil)
+			if err != nil {
+				t.Error(err)
+				return
+			}
+			defer conn.Close()
+			event := `<html><body><div class="state"><ul>` +
+				`<li class="elog-message-ev"><a href="/rw/elog/1/3?lang=en" rel="self"></a></li>` +
+				`</ul></div></body></html>`
+			if err := conn.WriteMessage(websocket.TextMessage, []byte(event)); err != nil {
+				t.Error(err)
+			}
+			for {
+				if _, _, err := conn.ReadMessage(); err != nil {
+					return
+				}
+			}
+		},
+		// the history after the clear, newest first, followed by a message from before the last position
+		"GET /rw/elog/1":   elogPage(message(2), message(1), []string{"/rw/elog/1/50", "msgtype", "1", "code", "10011", "tstamp", "2024-07-16 T 12:00:00", "title", "Motors ON state"}),
+		"GET /rw/elog/1/3": elogPage(message(3)),
+	})
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+	last := time.Date(2024, 7, 16, 12, 0, 0, 0, time.Local)
+	messages, err := client.SubscribeToElogMessages(ctx, map[int]structures.ElogPosition{1: {SeqNum: 50, Time: last}}, 1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var delivered []int
+	for len(delivered) < 3 {
+		select {
+		case message := <-messages:
+			delivered = append(delivered, message.SeqNum)
+		case <-ctx.Done():
+			t.Fatalf("timed out after %v (requests %v)", delivered, fake.Requests())
+		}
+	}
+	if fmt.Sprint(delivered) != "[1 2 3]" {
+		t.Errorf("unexpected delivered messages: %v", delivered)
+	}
+}
+
+func TestSubscribeToElogClosesOnDrop(t *testing.T) {
+	var mu sync.Mutex
+	connections := 0
+	upgrader := websocket.Upgrader{}
+	client, _ := newFakeController(t, map[string]http.HandlerFunc{
+		"POST /subscription": func(w http.ResponseWriter, r *http.Request) {
+			w.Header().Set("Location", "ws://"+r.Host+"/poll/1")
+			w.WriteHeader(http.StatusCreated)
+		},
+		"GET /poll/1": func(w http.ResponseWriter, r *http.Request) {
+			conn, err := upgrader.Upgrade(w, r, nil)
+			if err != nil {
+				t.Error(err)
+				return
+			}
+			mu.Lock()
+			connections++
+			mu.Unlock()
+			event := `<html><body><div class="state"><ul>` +
+				`<li class="elog-message-ev"><a href="/rw/elog/1/12?lang=en" rel="self"></a></li>` +
+				`</ul></div></body></html>`
+			if err := conn.WriteMessage(websocket.TextMessage, []byte(event)); err != nil {
+				t.Error(err)
+			}
+			// drop the connection after the event
+			conn.Close()
+		},
+		"GET /rw/elog/1/12": elogPage([]string{"/rw/elog/1/12", "msgtype", "1", "code", "10011", "tstamp", "2024-07-16 T 12:00:02", "title", "Motors ON state"}),
+	})
+	events, err := client.SubscribeToElog()
+	if err != nil {
+		t.Fatal(err)
+	}
+	timeout := time.After(10 * time.Second)
+	var received []map[string]string
+	for open := true; open; {
+		select {
+		case event, ok := <-events:
+			if ok {
+				received = append(received, event)
+			}
+			open = ok
+		case <-timeout:
+			t.Fatalf("channel not closed after the connection dropped, received %v", received)
+		}
+	}
+	if len(received) != 1 || received[0]["code"] != "10011" {
+		t.Errorf("unexpected events: %v", received)
+	}
+	mu.Lock()
+	defer mu.Unlock()
+	if connections != 1 {
+		t.Errorf("expected no reconnect, got %d connections", connections)
+	}
+}
+
+func TestWaitForFile(t *testing.T) {
+	interval := elogDumpPollInterval
+	elogDumpPollInterval = 10 * time.Millisecond
+	defer func() { elogDumpPollInterval = interval }()
+	sizes := []string{"", "512", "1024", "1024", "2048", "2048", "2048"}
+	polls := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if polls >= len(sizes) {
+			w.WriteHeader(http.StatusInternalServerError)
+			return
+		}
+		if sizes[polls] == "" {
+			polls++
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		w.Header().Set("Content-Length", sizes[polls])
+		polls++
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer server.Close()
+	client := NewClient(strings.TrimPrefix(server.URL, "http://"), "Default User", "robotics")
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+	if err := client.waitForFile(ctx, "$TEMP/elogdump.txt"); err != nil {
+		t.Fatal(err)
+	}
+	if polls != len(sizes) {
+		t.Errorf("expected %d polls, got %d", len(sizes), polls)
+	}
+	// errors other than a missing file are returned
+	if err := client.waitForFile(ctx, "$TEMP/elogdump.txt"); err == nil || ctx.Err() != nil {
+		t.Errorf("expected an HTTP error, got %v", err)
+	}
+	// a file that is never written times out without a deadline on the context
+	timeout := elogDumpTimeout
+	elogDumpTimeout = 50 * time.Millisecond
+	defer func() { elogDumpTimeout = timeout }()
+	polls, sizes = 0, make([]string, 100)
+	err := client.waitForFile(context.Background(), "$TEMP/missing.txt")
+	if !errors.Is(err, context.DeadlineExceeded) || !strings.Contains(err.Error(), "$TEMP/missing.txt") {
+		t.Errorf("expected a timeout naming the file, got %v", err)
+	}
+}
+
+func TestGetFileRemovesPartialFile(t *testing.T) {
+	client, _ := newFakeController(t, map[string]http.HandlerFunc{
+		"GET /fileservice/$TEMP/elogdump.txt": func(w http.ResponseWriter, r *http.Request) {
+			// the connection drops after a part of the announced content
+			w.Header().Set("Content-Length", "1024")
+			fmt.Fprint(w, "partial")
+		},
+	})
+	path := t.TempDir() + "/elogdump.txt"
+	if err := client.GetFile("$TEMP/elogdump.txt", path); err == nil {
+		t.Fatal("expected an error for a truncated download")
+	}
+	if _, err := os.Stat(path); !os.IsNotExist(err) {
+		t.Errorf("expected the partial file to be removed, got %v", err)
+	}
+}
+
+// fakeController is a test server that answers requests by method, path and action,
+// e.g. "POST /rw/mastership/motion?action=request". Unknown requests are answered with 404.
+type fakeController struct {
+	mu       sync.Mutex
+	routes   map[string]http.HandlerFunc
+	requests []string
+}
+
+func newFakeController(t *testing.T, Routes map[string]http.HandlerFunc) (*Client, *fakeController) {
+	fake := &fakeController{routes: Routes}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		key := r.Method + " " + r.URL.Path
+		if action := r.URL.Query().Get("action"); action != "" {
+			key += "?action=" + action
+		}
+		fake.mu.Lock()
+		fake.requests = append(fake.requests, key)
+		handler, ok := fake.routes[key]
+		fake.mu.Unlock()
+		if !ok {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		handler(w, r)
+	}))
+	t.Cleanup(server.Close)
+	return NewClient(strings.TrimPrefix(server.URL, "http://"), "Default User", "robotics"), fake
+}
+
+// Requests returns the requests received so far.
+func (f *fakeController) Requests() []string {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return append([]string(nil), f.requests...)
+}
+
+// stateJSON answers with a json=1 response containing a single _state entry.
+func stateJSON(State string) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprintf(w, `{"_links":{"base":{"href":"http://%s/"}},"_embedded":{"_state":[%s]}}`, r.Host, State)
+	}
+}
+
+// status answers with an empty response and the given status code.
+func status(Code int) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(Code)
+	}
+}
+
+func TestCalibrationSession(t *testing.T) {
+	client, fake := newFakeController(t, map[string]http.HandlerFunc{
+		"GET /rw/motionsystem/mechunits/ROB_1":                                      stateJSON(`{"_type":"ms-mechunit","_title":"ROB_1","axes":"7"}`),
+		"GET /rw/panel/opmode":                                                      stateJSON(`{"_type":"pnl-opmode","opmode":"MANR"}`),
+		"GET /rw/panel/ctrlstate":                                                   stateJSON(`{"_type":"pnl-ctrlstate","ctrlstate":"motoroff"}`),
+		"POST /rw/mastership/motion?action=request":                                 status(http.StatusNoContent),
+		"POST /rw/mastership/motion?action=release":                                 status(http.StatusInternalServerError),
+		"POST /rw/motionsystem/mechunits/ROB_1/axes/7?action=update-syncrevcounter": status(http.StatusNoContent),
+		"GET /rw/motionsystem/mechunits/ROB_1/axes/7":                               stateJSON(`{"_type":"ms-axis-calib","calib-status":"calibrated"}`),
+	})
+	session := client.NewCalibrationSession("ROB_1", CalibrationRevCounter)
+	report, err := session.Run()
+	if err == nil || !strings.Contains(err.Error(), "release motion mastership") {
+		t.Errorf("expected the release error, got %v", err)
+	}
+	if report == nil || len(report.Axes) != 7 {
+		t.Fatalf("expected a report for 7 axes, got %+v", report)
+	}
+	if report.Axes[6].Error != "" || report.Axes[6].Calibration.Status != "calibrated" {
+		t.Errorf("unexpected result for axis 7: %+v", report.Axes[6])
+	}
+	if report.Axes[0].Error == "" {
+		t.Errorf("expected an error for axis 1: %+v", report.Axes[0])
+	}
+	requests := fake.Requests()
+	if requests[len(requests)-1] != "POST /rw/mastership/motion?action=release" {
+		t.Errorf("mastership not released last: %v", requests)
+	}
+	if _, err := client.NewCalibrationSession("ROB_1", CalibrationFine, 8).Run(); err == nil {
+		t.Error("expected an error for axis 8 of a 7 axis unit")
+	}
+}
+
+func TestJog(t *testing.T) {
+	var jogBody url.Values
+	privilege := `{"_type":"user-rmmp","privilege":"modify"}`
+	routes := map[string]http.HandlerFunc{
+		"GET /rw/panel/opmode":                             stateJSON(`{"_type":"pnl-opmode","opmode":"MANR"}`),
+		"GET /users/rmmp":                                  func(w http.ResponseWriter, r *http.Request) { stateJSON(privilege)(w, r) },
+		"GET /rw/motionsystem/mechunits":                   stateJSON(`{"_type":"ms-mechunit-li","_title":"ROB_1"}`),
+		"POST /rw/mastership/motion?action=request":        status(http.StatusNoContent),
+		"POST /rw/mastership/motion?action=release":        status(http.StatusNoContent),
+		"POST /rw/motionsystem/mechunits/ROB_1?action=set": status(http.StatusNoContent),
+		"GET /rw/motionsystem":                             stateJSON(`{"_type":"ms-change-count","change-count":"42"}`),
+		"POST /rw/motionsystem?action=jog": func(w http.ResponseWriter, r *http.Request) {
+			if err := r.ParseForm(); err != nil {
+				t.Error(err)
+			}
+			jogBody = r.PostForm
+			w.WriteHeader(http.StatusNoContent)
+		},
+	}
+	client, fake := newFakeController(t, routes)
+	if err := client.Jog("ROB_1", structures.JogIncrements{Axis1: 1}, "Joint"); err == nil {
+		t.Error("expected an error before PrepareJog")
+	}
+	if err := client.PrepareJog("ROB_2"); err == nil {
+		t.Error("expected an error for an unknown mechunit")
+	}
+	if err := client.PrepareJog("ROB_1"); err != nil {
+		t.Fatal(err)
+	}
+	if err := client.Jog("ROB_1", structures.JogIncrements{Axis1: 101}, "Joint"); err == nil {
+		t.Error("expected an error for a jog value above 100")
+	}
+	if err := client.Jog("ROB_1", structures.JogIncrements{Axis1: 1}, "Joint2"); err == nil {
+		t.Error("expected an error for an invalid coordinate system")
+	}
+	if err := client.Jog("ROB_2", structures.JogIncrements{Axis1: 1}, "Joint"); err == nil {
+		t.Error("expected an error for a mechunit that was not prepared")
+	}
+	err := client.Jog("ROB_1", structures.JogIncrements{Axis1: 1, Axis3: -50}, "Joint")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if jogBody.Get("axis1") != "1" || jogBody.Get("axis3") != "-50" || jogBody.Get("ccount") != "42" || jogBody.Get("inc-mode") != "Small" {
+		t.Errorf("unexpected jog request: %v", jogBody)
+	}
+	before := len(fake.Requests())
+	if err := client.Jog("ROB_1", structures.JogIncrements{Axis1: 2}, "Joint"); err != nil {
+		t.Fatal(err)
+	}
+	step := fake.Requests()[before:]
+	want := []string{"GET /users/rmmp", "GET /rw/motionsystem", "POST /rw/motionsystem?action=jog"}
+	if strings.Join(step, ",") != strings.Join(want, ",") {
+		t.Errorf("unexpected requests for a jog step: got %v, want %v", step, want)
+	}
+	privilege = `{"_type":"user-rmmp","privilege":"none"}`
+	if err := client.Jog("ROB_1", structures.JogIncrements{Axis1: 1}, "Joint"); err == nil {
+		t.Error("expected an error without RMMP privilege")
+	}
+	if err := client.StopJog(); err != nil {
+		t.Fatal(err)
+	}
+	if err := client.Jog("ROB_1", structures.JogIncrements{Axis1: 1}, "Joint"); err == nil {
+		t.Error("expected an error after StopJog")
+	}
+}
+
+func TestSupervisionJson(t *testing.T) {
+	supervision := structures.SupervisionJson{}
+	//sample response for the motion supervision of ROB_1
+	data := `{
+    "_links": {
+        "base": {
+            "href": "http://localhost:80/rw/motionsystem/"
+        }
+    },
+    "_embedded": {
+        "_state": [
+            {
+                "_type": "ms-motionsupervision",
+                "_title": "motionsupervision",
+                "mode": "ON",
+                "level": "150"
+            }
+        ]
+    }
+}`
+	err := json.Unmarshal([]byte(data), &supervision)
+	if err != nil {
+		t.Fatalf("Error decoding response: %s", err)
+	}
+	state := supervision.Embedded.State[0]
+	if state.Mode != "ON" || state.Level != "150" {
+		t.Errorf("unexpected supervision: %+v", state)
+	}
+}
+
+func TestApplySupervisionRevert(t *testing.T) {
+	var modes []string
+	recordMode := func(w http.ResponseWriter, r *http.Request) {
+		if err := r.ParseForm(); err != nil {
+			t.Error(err)
+		}
+		modes = append(modes, r.PostForm.Get("mode"))
+		w.WriteHeader(http.StatusNoContent)
+	}
+	client, _ := newFakeController(t, map[string]http.HandlerFunc{
+		"GET /rw/motionsystem/motionsupervision":                   stateJSON(`{"_type":"ms-motionsupervision","mode":"true","level":"100"}`),
+		"POST /rw/motionsystem/motionsupervision?action=set-mode":  recordMode,
+		"POST /rw/motionsystem/motionsupervision?action=set-level": status(http.StatusBadRequest),
+		"GET /rw/motionsystem/pathsupervision":                     stateJSON(`{"_type":"ms-pathsupervision","mode":"ON","level":"80"}`),
+		"POST /rw/motionsystem/pathsupervision?action=set-mode":    recordMode,
+		"POST /rw/motionsystem/pathsupervision?action=set-level":   status(http.StatusNoContent),
+	})
+	motion, err := client.GetMotionSupervision("ROB_1")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !motion.Enabled || motion.Sensitivity != 100 {
+		t.Errorf("unexpected motion supervision: %+v", motion)
+	}
+	path, err := client.GetPathSupervision("ROB_1")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !path.Enabled || path.Level != 80 {
+		t.Errorf("unexpected path supervision: %+v", path)
+	}
+	err = client.ApplyMotionSupervision(structures.MotionSupervision{MechUnit: "ROB_1", Enabled: false, Sensitivity: 150})
+	if err == nil {
+		t.Fatal("expected the set-level error")
+	}
+	if fmt.Sprint(modes) != "[false true]" {
+		t.Errorf("expected the mode to be reverted, got %v", modes)
+	}
+	modes = nil
+	err = client.ApplyPathSupervision(structures.PathSupervision{MechUnit: "ROB_1", Enabled: false, Level: 120})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if fmt.Sprint(modes) != "[OFF]" {
+		t.Errorf("unexpected path supervision modes: %v", modes)
+	}
+	if err := client.ApplyPathSupervision(structures.PathSupervision{MechUnit: "ROB_1", Level: 301}); err == nil {
+		t.Error("expected an error for a level above the maximum")
+	}
+}
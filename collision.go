@@ -0,0 +1,194 @@
+package abb
+
+import (
+	"encoding/json"
+	"encoding/xml"
+	"fmt"
+	"net/http"
+	"sort"
+	"strconv"
+	"time"
+
+	"github.com/atmassey/abb-lib-rws/structures"
+)
+
+// CollisionElogCodes are the Elog codes that are reported when collision detection trips.
+// 50204: Motion supervision, 50056: Joint collision
+var CollisionElogCodes = []int{50204, 50056}
+
+// CollisionElogDomains are the Elog domains collision messages are logged in, motion and common.
+var CollisionElogDomains = []int{5, 0}
+
+// GetCollisionDetectionState returns the collision detection state of the controller.
+// Possible values: {INIT | TRIGGERED | CONFIRMED | TRIGGERED_ACK | UNDEFINED}
+func (c *Client) GetCollisionDetectionState() (string, error) {
+	var coldet structures.CollisionDetectionState
+	c.Client = c.DigestAuthenticate()
+	req, err := http.NewRequest("GET", "http://"+c.Host+"/rw/panel/coldetstate", nil)
+	if err != nil {
+		return "", err
+	}
+	q := req.URL.Query()
+	q.Add("json", "1")
+	req.URL.RawQuery = q.Encode()
+	resp, err := c.Client.Do(req)
+	if err != nil {
+		return "", err
+	}
+	if resp.StatusCode != http.StatusOK {
+		return "", fmt.Errorf("HTTP Status Code: %d", resp.StatusCode)
+	}
+	defer closeErrorCheck(resp.Body)
+	err = json.NewDecoder(resp.Body).Decode(&coldet)
+	if err != nil {
+		return "", err
+	}
+	if len(coldet.Embedded.State) == 0 {
+		return "", fmt.Errorf("Collision Detection State Not Found: %v", coldet)
+	}
+	return coldet.Embedded.State[0].ColdetState, nil
+}
+
+// SubscribeToCollisionDetection subscribes to the collision detection state and the collision
+// messages of the Elog. An event is sent every time the state changes and every time a collision
+// message is logged. Events from the Elog carry the code, time and mechanical unit of the collision.
+func (c *Client) SubscribeToCollisionDetection() (chan structures.CollisionEvent, error) {
+	mechUnits, err := c.GetMechUnits()
+	if err != nil {
+		return nil, err
+	}
+	state, err := c.GetCollisionDetectionState()
+	if err != nil {
+		return nil, err
+	}
+	resources := []string{"/rw/panel/coldetstate"}
+	for _, domain := range CollisionElogDomains {
+		resources = append(resources, "/rw/elog/"+strconv.Itoa(domain))
+	}
+	conn, err := c.subscribe(resources...)
+	if err != nil {
+		return nil, err
+	}
+	returnChannel := make(chan structures.CollisionEvent)
+	go func() {
+		defer func() {
+			conn.Close()
+			close(returnChannel)
+		}()
+		var last structures.CollisionEvent
+		for {
+			_, message, err := conn.ReadMessage()
+			if err != nil {
+				return
+			}
+			MessageXML := structures.SubscriptionEventXML{}
+			err = xml.Unmarshal(message, &MessageXML)
+			if err != nil {
+				return
+			}
+			for _, event := range MessageXML.Body.Div.List {
+				if len(event.Span) > 0 && event.Span[0].Class == "coldetstate" {
+					state = event.Span[0].Text
+					returnChannel <- structures.CollisionEvent{State: state, Time: time.Now()}
+					continue
+				}
+				domain, _, ok := elogRef(event.Link.Href)
+				if !ok {
+					continue
+				}
+				msg, err := c.getElogMessages(event.Link.Href, c.GetLanguage())
+				if err != nil {
+					continue
+				}
+				elog := decodeElogMessage(domain, event.Link.Href, msg.Body.Div.List.Span)
+				if !isCollisionCode(elog.Code) {
+					continue
+				}
+				// the same collision can be logged in both the motion and the common domain
+				if elog.Code == last.Code && elog.Timestamp.Equal(last.Time) {
+					continue
+				}
+				last = structures.CollisionEvent{
+					MechUnit: collisionMechUnit(elog, mechUnits.Title),
+					Time:     elog.Timestamp,
+					State:    state,
+					Code:     elog.Code,
+					Title:    elog.Title,
+				}
+				returnChannel <- last
+			}
+		}
+	}()
+	return returnChannel, nil
+}
+
+// CollisionHistory returns the newest Limit collisions logged in the motion and common Elog domains,
+// newest first. The most recent collision reports the current collision detection state as its
+// recovery state, a Limit of 0 returns all collisions in the Elog.
+func (c *Client) CollisionHistory(Limit int) ([]structures.Collision, error) {
+	mechUnits, err := c.GetMechUnits()
+	if err != nil {
+		return nil, err
+	}
+	state, err := c.GetCollisionDetectionState()
+	if err != nil {
+		return nil, err
+	}
+	filter := structures.ElogFilter{Limit: Limit}
+	for _, code := range CollisionElogCodes {
+		filter.Codes = append(filter.Codes, structures.ElogCodeRange{Min: code, Max: code})
+	}
+	var collisions []structures.Collision
+	seen := make(map[string]bool)
+	for _, domain := range CollisionElogDomains {
+		messages, err := c.GetElogMessages(domain, filter)
+		if err != nil {
+			return nil, err
+		}
+		for _, message := range messages {
+			// the same collision can be logged in both the motion and the common domain
+			key := strconv.Itoa(message.Code) + "@" + message.Timestamp.String()
+			if seen[key] {
+				continue
+			}
+			seen[key] = true
+			collisions = append(collisions, structures.Collision{
+				MechUnit: collisionMechUnit(message, mechUnits.Title),
+				Time:     message.Timestamp,
+				Code:     message.Code,
+				Title:    message.Title,
+			})
+		}
+	}
+	sort.SliceStable(collisions, func(i, j int) bool {
+		return collisions[i].Time.After(collisions[j].Time)
+	})
+	if Limit > 0 && len(collisions) > Limit {
+		collisions = collisions[:Limit]
+	}
+	if len(collisions) > 0 && state != "INIT" {
+		collisions[0].RecoveryState = state
+	}
+	return collisions, nil
+}
+
+func isCollisionCode(Code int) bool {
+	for _, code := range CollisionElogCodes {
+		if code == Code {
+			return true
+		}
+	}
+	return false
+}
+
+// collisionMechUnit finds the mechanical unit named in the arguments of a collision message.
+func collisionMechUnit(Message structures.ElogMessage, MechUnits []string) string {
+	for _, arg := range Message.Args {
+		for _, unit := range MechUnits {
+			if arg == unit {
+				return unit
+			}
+		}
+	}
+	return ""
+}
@@ -0,0 +1,296 @@
+package abb
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/atmassey/abb-lib-rws/cfgfile"
+	"github.com/atmassey/abb-lib-rws/structures"
+)
+
+// VolatileCfgAttributes are attributes that differ between otherwise identical cells and are ignored
+// by DiffConfig, by domain/type. An entry of "*" ignores the whole type.
+var VolatileCfgAttributes = map[string][]string{
+	"MOC/MOTOR_CALIB":         {"cal_offset", "cal_offset_valid", "com_offset", "valid_com_offset", "factory_calib_method", "latest_calib_method"},
+	"MOC/ROBOT_SERIAL_NUMBER": {"*"},
+}
+
+// ConfigSource is a configuration that can be compared with DiffConfig, either a *Client or a BackupDir.
+type ConfigSource interface {
+	ConfigDomains() (map[string]*cfgfile.File, error)
+}
+
+// BackupDir is a local backup directory, the configuration is read from its SYSPAR directory.
+type BackupDir string
+
+// ConfigDomains returns the configuration files of the backup by domain.
+func (d BackupDir) ConfigDomains() (map[string]*cfgfile.File, error) {
+	paths, err := filepath.Glob(filepath.Join(string(d), "SYSPAR", "*.cfg"))
+	if err != nil {
+		return nil, err
+	}
+	if len(paths) == 0 {
+		return nil, fmt.Errorf("no configuration files found in %s", filepath.Join(string(d), "SYSPAR"))
+	}
+	domains := make(map[string]*cfgfile.File)
+	for _, path := range paths {
+		file, err := parseCfgFile(path)
+		if err != nil {
+			return nil, err
+		}
+		domains[file.Domain] = file
+	}
+	return domains, nil
+}
+
+// ConfigDomains saves every configuration domain of the controller and returns the parsed files by domain.
+func (c *Client) ConfigDomains() (map[string]*cfgfile.File, error) {
+	names, err := c.ListCfgDomains()
+	if err != nil {
+		return nil, err
+	}
+	dir, err := os.MkdirTemp("", "abb-cfg")
+	if err != nil {
+		return nil, err
+	}
+	defer os.RemoveAll(dir)
+	domains := make(map[string]*cfgfile.File)
+	for _, name := range names {
+		path := filepath.Join(dir, name+".cfg")
+		err = c.SaveCfgDomain(name, path)
+		if err != nil {
+			return nil, err
+		}
+		file, err := parseCfgFile(path)
+		if err != nil {
+			return nil, err
+		}
+		domains[name] = file
+	}
+	return domains, nil
+}
+
+// DiffConfig compares the configuration of two controllers or backups instance by instance and
+// attribute by attribute. Comments, ordering and the attributes in VolatileCfgAttributes are ignored.
+// Example: DiffConfig(client, abb.BackupDir("backups/cell2"))
+func DiffConfig(A ConfigSource, B ConfigSource) (*structures.ConfigDiff, error) {
+	a, err := A.ConfigDomains()
+	if err != nil {
+		return nil, err
+	}
+	b, err := B.ConfigDomains()
+	if err != nil {
+		return nil, err
+	}
+	return &structures.ConfigDiff{
+		A:       configSourceName(A),
+		B:       configSourceName(B),
+		Changes: diffCfgDomains(a, b),
+	}, nil
+}
+
+// WriteConfigDiffReport writes a human readable report of a configuration diff.
+func WriteConfigDiffReport(W io.Writer, Diff *structures.ConfigDiff) error {
+	var b strings.Builder
+	fmt.Fprintf(&b, "Configuration diff\nA: %s\nB: %s\n", Diff.A, Diff.B)
+	if Diff.Equal() {
+		b.WriteString("\nThe configurations are identical.\n")
+	}
+	section := ""
+	for _, change := range Diff.Changes {
+		if heading := change.Domain + "/" + change.Type; heading != section {
+			section = heading
+			fmt.Fprintf(&b, "\n%s\n", strings.TrimSuffix(heading, "/"))
+		}
+		target := change.Instance
+		if change.Type == "" {
+			target = "domain " + change.Domain
+		}
+		if change.Attribute != "" {
+			target += " -" + change.Attribute
+		}
+		switch change.Kind {
+		case structures.CfgChanged:
+			fmt.Fprintf(&b, "  ~ %s: %q -> %q\n", target, change.A, change.B)
+		case structures.CfgAdded:
+			fmt.Fprintf(&b, "  + %s\n", strings.TrimSpace(target+" "+change.B))
+		case structures.CfgRemoved:
+			fmt.Fprintf(&b, "  - %s\n", strings.TrimSpace(target+" "+change.A))
+		case structures.CfgDuplicate:
+			fmt.Fprintf(&b, "  ! %s: defined %s time(s) in A and %s time(s) in B\n", target, change.A, change.B)
+		}
+	}
+	fmt.Fprintf(&b, "\n%d difference(s)\n", len(Diff.Changes))
+	_, err := io.WriteString(W, b.String())
+	return err
+}
+
+func configSourceName(Source ConfigSource) string {
+	switch source := Source.(type) {
+	case *Client:
+		return source.Host
+	case BackupDir:
+		return string(source)
+	default:
+		return fmt.Sprintf("%T", Source)
+	}
+}
+
+func parseCfgFile(Path string) (*cfgfile.File, error) {
+	f, err := os.Open(Path)
+	if err != nil {
+		return nil, err
+	}
+	defer closeFileCheck(f)
+	file, err := cfgfile.Parse(f)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", Path, err)
+	}
+	return file, nil
+}
+
+// diffCfgDomains compares two sets of configuration files and returns the changes sorted by domain, type and instance.
+func diffCfgDomains(A map[string]*cfgfile.File, B map[string]*cfgfile.File) []structures.CfgChange {
+	var changes []structures.CfgChange
+	for _, domain := range unionKeys(A, B) {
+		a, b := A[domain], B[domain]
+		switch {
+		case a == nil:
+			changes = append(changes, structures.CfgChange{Kind: structures.CfgAdded, Domain: domain})
+			continue
+		case b == nil:
+			changes = append(changes, structures.CfgChange{Kind: structures.CfgRemoved, Domain: domain})
+			continue
+		}
+		typesA, duplicatesA := cfgInstancesByType(a)
+		typesB, duplicatesB := cfgInstancesByType(b)
+		for _, typ := range unionKeys(typesA, typesB) {
+			volatile := VolatileCfgAttributes[domain+"/"+typ]
+			if containsString(volatile, "*") {
+				continue
+			}
+			instancesA, instancesB := typesA[typ], typesB[typ]
+			for _, name := range unionKeys(instancesA, instancesB) {
+				change := structures.CfgChange{Domain: domain, Type: typ, Instance: name}
+				if countA, countB := duplicatesA[typ][name], duplicatesB[typ][name]; countA > 0 || countB > 0 {
+					changes = append(changes, structures.CfgChange{
+						Kind:     structures.CfgDuplicate,
+						Domain:   domain,
+						Type:     typ,
+						Instance: name,
+						A:        strconv.Itoa(countA),
+						B:        strconv.Itoa(countB),
+					})
+				}
+				instanceA, instanceB := instancesA[name], instancesB[name]
+				switch {
+				case instanceA == nil:
+					change.Kind = structures.CfgAdded
+					changes = append(changes, change)
+					continue
+				case instanceB == nil:
+					change.Kind = structures.CfgRemoved
+					changes = append(changes, change)
+					continue
+				}
+				attributesA, attributesB := instanceA.Map(), instanceB.Map()
+				for _, attribute := range unionKeys(attributesA, attributesB) {
+					if containsString(volatile, attribute) {
+						continue
+					}
+					valueA, okA := attributesA[attribute]
+					valueB, okB := attributesB[attribute]
+					change.Attribute, change.A, change.B = attribute, valueA, valueB
+					switch {
+					case !okA:
+						change.Kind = structures.CfgAdded
+					case !okB:
+						change.Kind = structures.CfgRemoved
+					case !cfgValuesEqual(valueA, valueB):
+						change.Kind = structures.CfgChanged
+					default:
+						continue
+					}
+					changes = append(changes, change)
+				}
+			}
+		}
+	}
+	return changes
+}
+
+// cfgInstancesByType returns the instances of a file by type and name, and the number of occurrences
+// of every name that is used more than once within a type. The last instance of a duplicate name is kept.
+// Instances without a name are keyed by their attributes, e.g. #{-axis "1" -value "0"}.
+func cfgInstancesByType(File *cfgfile.File) (map[string]map[string]*cfgfile.Instance, map[string]map[string]int) {
+	types := make(map[string]map[string]*cfgfile.Instance)
+	duplicates := make(map[string]map[string]int)
+	for _, typ := range File.Types {
+		if types[typ.Name] == nil {
+			types[typ.Name] = make(map[string]*cfgfile.Instance)
+		}
+		counts := make(map[string]int)
+		unnamed := make(map[string]int)
+		for _, instance := range typ.Instances {
+			name := instance.Name()
+			if name == "" {
+				name = cfgInstanceContentKey(instance)
+				unnamed[name]++
+				if unnamed[name] > 1 {
+					// identical unnamed instances are kept apart by their occurrence
+					name += " (" + strconv.Itoa(unnamed[name]) + ")"
+				}
+			} else {
+				counts[name]++
+			}
+			types[typ.Name][name] = instance
+		}
+		for name, count := range counts {
+			if count > 1 {
+				if duplicates[typ.Name] == nil {
+					duplicates[typ.Name] = make(map[string]int)
+				}
+				duplicates[typ.Name][name] = count
+			}
+		}
+	}
+	return types, duplicates
+}
+
+// cfgInstanceContentKey identifies an unnamed instance by its attributes in name order.
+func cfgInstanceContentKey(Instance *cfgfile.Instance) string {
+	attributes := Instance.Map()
+	names := make([]string, 0, len(attributes))
+	for name := range attributes {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	parts := make([]string, 0, len(names))
+	for _, name := range names {
+		if attributes[name] == "" {
+			parts = append(parts, "-"+name)
+		} else {
+			parts = append(parts, "-"+name+" "+strconv.Quote(attributes[name]))
+		}
+	}
+	return "#{" + strings.Join(parts, " ") + "}"
+}
+
+func unionKeys[V any](A map[string]V, B map[string]V) []string {
+	keys := make([]string, 0, len(A)+len(B))
+	for key := range A {
+		keys = append(keys, key)
+	}
+	for key := range B {
+		if _, ok := A[key]; !ok {
+			keys = append(keys, key)
+		}
+	}
+	sort.Strings(keys)
+	return keys
+}
@@ -2,12 +2,14 @@ package abb
 
 import (
 	"bytes"
+	"encoding/json"
 	"encoding/xml"
 	"fmt"
 	"io"
 	"net/http"
 	"net/url"
 	"strconv"
+	"strings"
 
 	"github.com/atmassey/abb-lib-rws/structures"
 )
@@ -98,6 +100,66 @@ func (c *Client) SetControllerLanguage(language string) error {
 	return nil
 }
 
+// GetControllerLanguages returns the languages installed on the controller, e.g. "en", "de" and "es".
+func (c *Client) GetControllerLanguages() ([]string, error) {
+	var languagesRaw structures.ControllerLanguagesJson
+	c.Client = c.DigestAuthenticate()
+	req, err := http.NewRequest("GET", "http://"+c.Host+"/rw/system/languages", nil)
+	if err != nil {
+		return nil, err
+	}
+	q := req.URL.Query()
+	q.Add("json", "1")
+	req.URL.RawQuery = q.Encode()
+	resp, err := c.Client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer closeErrorCheck(resp.Body)
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("HTTP Status Code: %d", resp.StatusCode)
+	}
+	err = json.NewDecoder(resp.Body).Decode(&languagesRaw)
+	if err != nil {
+		return nil, err
+	}
+	var languages []string
+	for _, state := range languagesRaw.Embedded.State {
+		if state.Lang != "" {
+			languages = append(languages, state.Lang)
+		} else {
+			languages = append(languages, state.Title)
+		}
+	}
+	return languages, nil
+}
+
+// SetLanguage sets the preferred language of localized resources like Elog messages for this client.
+// The language must be installed on the controller, see GetControllerLanguages.
+// Unlike SetControllerLanguage this does not change the language of the controller itself.
+func (c *Client) SetLanguage(Language string) error {
+	err := c.checkLanguages(Language)
+	if err != nil {
+		return err
+	}
+	c.Language = Language
+	return nil
+}
+
+// checkLanguages is a helper function that returns an error if a language is not installed on the controller.
+func (c *Client) checkLanguages(Languages ...string) error {
+	installed, err := c.GetControllerLanguages()
+	if err != nil {
+		return err
+	}
+	for _, language := range Languages {
+		if !containsString(installed, language) {
+			return fmt.Errorf("language %s is not installed on the controller, available: %s", language, strings.Join(installed, ", "))
+		}
+	}
+	return nil
+}
+
 // CompressionResource will compress or decompress a file a give path
 // comp must be either "comp" for compression or "dcomp" for decompression
 func (c *Client) CompressionResource(srcpath string, dstpath string, comp string) error {
@@ -0,0 +1,77 @@
+// Package eio parses the EIO domain of ABB configuration files (EIO.cfg) into typed structs
+// and generates Go constants for the signal paths so they can be checked at compile time.
+package eio
+
+// Network is an INDUSTRIAL_NETWORK instance.
+type Network struct {
+	Name       string
+	Label      string
+	Address    string
+	Attributes map[string]string
+}
+
+// Device is an instance of one of the device types, e.g. ETHERNETIP_DEVICE or PROFINET_DEVICE.
+type Device struct {
+	// Type is the configuration type the device was declared in.
+	Type       string
+	Name       string
+	Label      string
+	Network    string
+	Address    string
+	VendorID   string
+	ProductID  string
+	Attributes map[string]string
+}
+
+// Signal is an EIO_SIGNAL instance.
+type Signal struct {
+	Name       string
+	SignalType string
+	Device     string
+	DeviceMap  string
+	Category   string
+	Access     string
+	Default    string
+	Invert     bool
+	Attributes map[string]string
+}
+
+// CrossActor is one actor of a cross connection.
+type CrossActor struct {
+	Signal string
+	Invert bool
+	// Operator combines this actor with the next one, either AND or OR. Empty for the last actor.
+	Operator string
+}
+
+// Cross is an EIO_CROSS instance.
+type Cross struct {
+	Name       string
+	Result     string
+	Actors     []CrossActor
+	Attributes map[string]string
+}
+
+// Config is the decoded EIO domain.
+type Config struct {
+	// Header is the first line of the file, e.g. EIO:CFG_1.0:6:1::
+	Header           string
+	Networks         []Network
+	Devices          []Device
+	Signals          []Signal
+	CrossConnections []Cross
+}
+
+// Path returns the path of a signal as used by the IO system of the controller,
+// <network>/<device>/<signal>, or only the signal name if it is not mapped to a device.
+func (c *Config) Path(Signal Signal) string {
+	if Signal.Device == "" {
+		return Signal.Name
+	}
+	for _, device := range c.Devices {
+		if device.Name == Signal.Device && device.Network != "" {
+			return device.Network + "/" + device.Name + "/" + Signal.Name
+		}
+	}
+	return Signal.Device + "/" + Signal.Name
+}
@@ -0,0 +1,97 @@
+package eio
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+)
+
+const sampleEIO = `EIO:CFG_1.0:6:1::
+#
+INDUSTRIAL_NETWORK:
+
+      -Name "EtherNetIP" -Label "EtherNet/IP Network" -Connection "LAN3"
+#
+ETHERNETIP_DEVICE:
+
+      -Name "d651" -VendorName "ABB Robotics" -ProductName "DSQC 651"\
+      -Network "EtherNetIP" -Address "192.168.125.100" -VendorId 75\
+      -ProductCode 651
+#
+EIO_SIGNAL:
+
+      -Name "do_Grip_Close" -SignalType "DO" -Device "d651" -DeviceMap "0"
+
+      -Name "diGripClosed" -SignalType "DI" -Device "d651" -DeviceMap "1"\
+      -Invert -Access "All"
+
+      -Name "aoSpeed" -SignalType "AO" -MinLog -10 -MaxLog 10
+
+      -Name "diVirtual" -SignalType "DI"
+#
+EIO_CROSS:
+
+      -Name "cross_grip" -Res "diVirtual" -Act1 "do_Grip_Close" -Oper1 "AND"\
+      -Act2 "diGripClosed" -Act2_invert
+`
+
+func TestParse(t *testing.T) {
+	config, err := Parse(strings.NewReader(sampleEIO))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(config.Networks) != 1 || len(config.Devices) != 1 || len(config.Signals) != 4 || len(config.CrossConnections) != 1 {
+		t.Fatalf("unexpected config: %+v", config)
+	}
+	if config.Devices[0].ProductID != "651" || config.Devices[0].Network != "EtherNetIP" {
+		t.Errorf("unexpected device: %+v", config.Devices[0])
+	}
+	if !config.Signals[1].Invert || config.Signals[1].Access != "All" {
+		t.Errorf("unexpected signal: %+v", config.Signals[1])
+	}
+	if config.Signals[2].Attributes["MinLog"] != "-10" {
+		t.Errorf("unexpected MinLog: %q", config.Signals[2].Attributes["MinLog"])
+	}
+	cross := config.CrossConnections[0]
+	if len(cross.Actors) != 2 || !cross.Actors[1].Invert || cross.Actors[0].Operator != "AND" {
+		t.Errorf("unexpected cross connection: %+v", cross)
+	}
+	if path := config.Path(config.Signals[0]); path != "EtherNetIP/d651/do_Grip_Close" {
+		t.Errorf("unexpected path: %s", path)
+	}
+}
+
+func TestCrossFromAttributes(t *testing.T) {
+	// attributes as reported by the controller, unused actors and inversions are present but empty or false
+	cross := CrossFromAttributes(map[string]string{
+		"Name":        "cross_1",
+		"Res":         "doResult",
+		"Act1":        "di1",
+		"Act1_invert": "false",
+		"Oper1":       "AND",
+		"Act2":        "di2",
+		"Act2_invert": "true",
+		"Oper2":       "OR",
+		"Act3":        "",
+	})
+	if len(cross.Actors) != 2 || cross.Actors[0].Invert || !cross.Actors[1].Invert || cross.Actors[1].Operator != "" {
+		t.Errorf("unexpected cross connection: %+v", cross)
+	}
+}
+
+func TestGenerateConstants(t *testing.T) {
+	config, err := Parse(strings.NewReader(sampleEIO))
+	if err != nil {
+		t.Fatal(err)
+	}
+	var source bytes.Buffer
+	if err := GenerateConstants(&source, config, "signals"); err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(source.String(), `SignalDoGripClose = "EtherNetIP/d651/do_Grip_Close"`) {
+		t.Errorf("unexpected source:\n%s", source.String())
+	}
+	if _, err := Parse(strings.NewReader("MOC:CFG_1.0:6:1::\n")); err == nil {
+		t.Error("expected error for non EIO file")
+	}
+}
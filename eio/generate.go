@@ -0,0 +1,61 @@
+package eio
+
+import (
+	"bytes"
+	"fmt"
+	"go/format"
+	"io"
+	"sort"
+	"strings"
+	"unicode"
+)
+
+// GenerateConstants writes a Go source file declaring a constant with the controller path of every signal,
+// e.g. const SignalDoGripClose = "EtherNetIP/d651/doGripClose".
+// The constants can be passed to SubscribeToIOSignal, GetSignal and the other signal functions.
+func GenerateConstants(W io.Writer, Config *Config, Package string) error {
+	var b bytes.Buffer
+	fmt.Fprintf(&b, "// Code generated by eio.GenerateConstants from %s. DO NOT EDIT.\n\n", Config.Header)
+	fmt.Fprintf(&b, "package %s\n\n", Package)
+	signals := append([]Signal(nil), Config.Signals...)
+	sort.Slice(signals, func(i, j int) bool { return signals[i].Name < signals[j].Name })
+	seen := make(map[string]string)
+	b.WriteString("const (\n")
+	for _, signal := range signals {
+		name := ConstantName(signal.Name)
+		if other, ok := seen[name]; ok {
+			return fmt.Errorf("signals %s and %s both map to constant %s", other, signal.Name, name)
+		}
+		seen[name] = signal.Name
+		if signal.SignalType != "" {
+			fmt.Fprintf(&b, "\t// %s is a %s signal.\n", name, signal.SignalType)
+		}
+		fmt.Fprintf(&b, "\t%s = %q\n", name, Config.Path(signal))
+	}
+	b.WriteString(")\n")
+	source, err := format.Source(b.Bytes())
+	if err != nil {
+		return err
+	}
+	_, err = W.Write(source)
+	return err
+}
+
+// ConstantName returns the Go constant name for a signal, e.g. do_Grip_Close -> SignalDoGripClose.
+func ConstantName(Signal string) string {
+	var b strings.Builder
+	b.WriteString("Signal")
+	upper := true
+	for _, r := range Signal {
+		if !unicode.IsLetter(r) && !unicode.IsDigit(r) {
+			upper = true
+			continue
+		}
+		if upper {
+			r = unicode.ToUpper(r)
+			upper = false
+		}
+		b.WriteRune(r)
+	}
+	return b.String()
+}
@@ -0,0 +1,104 @@
+package eio
+
+import (
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/atmassey/abb-lib-rws/cfgfile"
+)
+
+// Parse decodes the EIO domain of an ABB configuration file.
+// Instances of types other than INDUSTRIAL_NETWORK, *_DEVICE, EIO_SIGNAL and EIO_CROSS are ignored.
+func Parse(R io.Reader) (*Config, error) {
+	file, err := cfgfile.Parse(R)
+	if err != nil {
+		return nil, err
+	}
+	if file.Domain != "EIO" {
+		return nil, fmt.Errorf("not an EIO configuration file: %q", file.Header)
+	}
+	config := Config{Header: file.Header}
+	for _, typ := range file.Types {
+		for _, inst := range typ.Instances {
+			config.add(typ.Name, inst.Map())
+		}
+	}
+	return &config, nil
+}
+
+// add decodes a single instance of the given type into the config.
+func (c *Config) add(Type string, a map[string]string) {
+	switch {
+	case Type == "INDUSTRIAL_NETWORK":
+		c.Networks = append(c.Networks, Network{
+			Name:       a["Name"],
+			Label:      a["Label"],
+			Address:    a["Address"],
+			Attributes: a,
+		})
+	case Type == "DEVICE" || strings.HasSuffix(Type, "_DEVICE"):
+		c.Devices = append(c.Devices, Device{
+			Type:       Type,
+			Name:       a["Name"],
+			Label:      a["Label"],
+			Network:    a["Network"],
+			Address:    a["Address"],
+			VendorID:   a["VendorId"],
+			ProductID:  a["ProductCode"],
+			Attributes: a,
+		})
+	case Type == "EIO_SIGNAL":
+		c.Signals = append(c.Signals, Signal{
+			Name:       a["Name"],
+			SignalType: a["SignalType"],
+			Device:     a["Device"],
+			DeviceMap:  a["DeviceMap"],
+			Category:   a["Category"],
+			Access:     a["Access"],
+			Default:    a["Default"],
+			Invert:     isSet(a, "Invert"),
+			Attributes: a,
+		})
+	case Type == "EIO_CROSS":
+		c.CrossConnections = append(c.CrossConnections, CrossFromAttributes(a))
+	}
+}
+
+// CrossFromAttributes decodes the attributes of an EIO_CROSS instance, either read from a configuration
+// file or from the controller. The actors are stored as Act1..Act5 with Act<n>_invert and the operator
+// Oper<n> to the next actor. Act<n>_invert is set when it is a flag without a value, as in a configuration
+// file, or when its value is true, as reported by the controller.
+func CrossFromAttributes(Attributes map[string]string) Cross {
+	cross := Cross{Name: Attributes["Name"], Result: Attributes["Res"], Attributes: Attributes}
+	for i := 1; i <= 5; i++ {
+		n := fmt.Sprint(i)
+		signal := Attributes["Act"+n]
+		if signal == "" {
+			break
+		}
+		cross.Actors = append(cross.Actors, CrossActor{
+			Signal:   signal,
+			Invert:   isSet(Attributes, "Act"+n+"_invert"),
+			Operator: Attributes["Oper"+n],
+		})
+	}
+	if len(cross.Actors) > 0 {
+		cross.Actors[len(cross.Actors)-1].Operator = ""
+	}
+	return cross
+}
+
+// isSet reports whether a boolean attribute is a flag or has a true value.
+func isSet(Attributes map[string]string, Name string) bool {
+	value, ok := Attributes[Name]
+	if !ok {
+		return false
+	}
+	switch strings.ToLower(value) {
+	case "", "true", "1", "yes", "on":
+		return true
+	default:
+		return false
+	}
+}
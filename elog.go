@@ -2,18 +2,31 @@ package abb
 
 import (
 	"bytes"
+	"context"
 	"encoding/xml"
 	"fmt"
 	"io"
 	"net/http"
 	"net/url"
+	"os"
+	"path/filepath"
+	"strconv"
 	"strings"
 	"time"
 
 	"github.com/atmassey/abb-lib-rws/structures"
-	"github.com/gorilla/websocket"
 )
 
+// elogDumpPollInterval is the interval in which DownloadElogSystemDump checks whether the dump is complete.
+var elogDumpPollInterval = time.Second
+
+// elogDumpStablePolls is the number of consecutive polls that must report the same size of the dump
+// before it is considered complete. The controller writes the dump in chunks with pauses in between.
+const elogDumpStablePolls = 3
+
+// elogDumpTimeout is the longest DownloadElogSystemDump waits for the dump when the context has no deadline.
+var elogDumpTimeout = 2 * time.Minute
+
 // SaveElogSystemDump dumps log file to the specified path on the controller.
 // Example path: $HOME/my_dump_file.txt
 func (c *Client) SaveElogSystemDump(Path string) error {
@@ -39,9 +52,101 @@ func (c *Client) SaveElogSystemDump(Path string) error {
 	return nil
 }
 
+// DownloadElogSystemDump saves an Elog system dump to $TEMP on the controller, waits until the file is
+// complete and downloads it. The file on the controller is deleted afterwards. LocalPath can be a file
+// or an existing directory, the path of the downloaded file is returned.
+// The dump is complete once its size is the same on elogDumpStablePolls consecutive polls. Without a
+// deadline on the context the wait is limited to elogDumpTimeout.
+func (c *Client) DownloadElogSystemDump(Ctx context.Context, LocalPath string) (string, error) {
+	name := "elogdump_" + time.Now().Format("20060102_150405") + ".txt"
+	remote := "$TEMP/" + name
+	if info, err := os.Stat(LocalPath); err == nil && info.IsDir() {
+		LocalPath = filepath.Join(LocalPath, name)
+	}
+	err := c.SaveElogSystemDump(remote)
+	if err != nil {
+		return "", err
+	}
+	err = c.waitForFile(Ctx, remote)
+	if err == nil {
+		err = c.GetFile(remote, LocalPath)
+	}
+	if deleteErr := c.DeleteFile(remote); deleteErr != nil {
+		if err != nil {
+			return "", err
+		}
+		return LocalPath, fmt.Errorf("elog dump downloaded but not deleted from the controller: %w", deleteErr)
+	}
+	if err != nil {
+		return "", err
+	}
+	return LocalPath, nil
+}
+
+// waitForFile is a helper function that polls the size of a file on the controller until it exists
+// and has the same size on elogDumpStablePolls consecutive polls. A missing file is polled again,
+// any other error is returned. A context without a deadline is limited to elogDumpTimeout.
+func (c *Client) waitForFile(Ctx context.Context, Path string) error {
+	if _, ok := Ctx.Deadline(); !ok {
+		var cancel context.CancelFunc
+		Ctx, cancel = context.WithTimeout(Ctx, elogDumpTimeout)
+		defer cancel()
+	}
+	ticker := time.NewTicker(elogDumpPollInterval)
+	defer ticker.Stop()
+	previous := ""
+	stable := 0
+	for {
+		select {
+		case <-Ctx.Done():
+			return fmt.Errorf("file %s not complete: %w", Path, Ctx.Err())
+		case <-ticker.C:
+		}
+		size, err := c.fileSize(Path)
+		if err != nil {
+			return err
+		}
+		if size == "" || size == "0" {
+			// the file is not created yet
+			previous, stable = "", 0
+			continue
+		}
+		if size != previous {
+			previous, stable = size, 0
+		}
+		stable++
+		if stable >= elogDumpStablePolls {
+			return nil
+		}
+	}
+}
+
+// fileSize is a helper function that returns the size of a file on the controller, "" if the file doesn't exist.
+func (c *Client) fileSize(Path string) (string, error) {
+	c.Client = c.DigestAuthenticate()
+	req, err := http.NewRequest("HEAD", "http://"+c.Host+"/fileservice/"+Path, nil)
+	if err != nil {
+		return "", err
+	}
+	resp, err := c.Client.Do(req)
+	if err != nil {
+		return "", err
+	}
+	defer closeErrorCheck(resp.Body)
+	switch resp.StatusCode {
+	case http.StatusOK, http.StatusNoContent:
+		return resp.Header.Get("Content-Length"), nil
+	case http.StatusNotFound:
+		return "", nil
+	default:
+		return "", fmt.Errorf("HTTP Status Code: %d", resp.StatusCode)
+	}
+}
+
 // getElogMessages is a helper function that returns the messages from the Elog system based on the endpoint.
 // This function is used in conjunction with SubscribeToElog for the Elog websocket.
-func (c *Client) getElogMessages(Endpoint string) (*structures.ElogMessagesXML, error) {
+// The language of the endpoint is replaced with the given language.
+func (c *Client) getElogMessages(Endpoint string, Language string) (*structures.ElogMessagesXML, error) {
 	var messages structures.ElogMessagesXML
 	c.Client = c.DigestAuthenticate()
 	req, err := http.NewRequest("GET", "http://"+c.Host+Endpoint, nil)
@@ -49,15 +154,15 @@ func (c *Client) getElogMessages(Endpoint string) (*structures.ElogMessagesXML,
 		return nil, err
 	}
 	q := req.URL.Query()
-	q.Add("lang", "en")
+	q.Set("lang", Language)
 	req.URL.RawQuery = q.Encode()
 	resp, err := c.Client.Do(req)
-	defer closeErrorCheck(resp.Body)
 	if err != nil {
 		return nil, err
 	}
+	defer closeErrorCheck(resp.Body)
 	if resp.StatusCode != http.StatusOK {
-		return nil, fmt.Errorf("http status code: %w", err)
+		return nil, fmt.Errorf("HTTP Status Code: %d", resp.StatusCode)
 	}
 	messages_raw, err := io.ReadAll(resp.Body)
 	if err != nil {
@@ -72,91 +177,66 @@ func (c *Client) getElogMessages(Endpoint string) (*structures.ElogMessagesXML,
 
 // SubscribeToElog subscribes to the Elog websocket for all events happening at the robot.
 // This function returns a map of strings. The keys within the map are as follows
-// "msgtype", "code", "tstamp", "title", "desc", "conseqs", "causes", "actions", "argc",
-// "arg1", and "arg2".
+// "domain", "seqnum", "msgtype", "code", "tstamp", "title", "desc", "conseqs", "causes", "actions", "argc",
+// "arg1", and "arg2". Use DecodeElogEvent to convert an event into a typed message.
+// The channel is closed when the websocket connection drops. Use SubscribeToElogMessages to
+// reconnect and backfill missed messages automatically.
 func (c *Client) SubscribeToElog() (chan map[string]string, error) {
-	returnChannel := make(chan map[string]string)
-	body := url.Values{}
-	body.Add("resources", "1")
-	body.Add("1", "/rw/elog/1")
-	body.Add("1-p", "1")
-	c.Client = c.DigestAuthenticate()
-	req, err := http.NewRequest("POST", "http://"+c.Host+"/subscription", bytes.NewBufferString(body.Encode()))
-	if err != nil {
-		return nil, err
-	}
-	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
-	resp, err := c.Client.Do(req)
-	if err != nil {
-		return nil, err
-	}
-	defer closeErrorCheck(resp.Body)
-	if resp.StatusCode != http.StatusCreated {
-		return nil, fmt.Errorf("HTTP Status Code: %d", resp.StatusCode)
-	}
-	ws_url := resp.Header.Get("Location")
-	header := resp.Cookies()
-	var session, session_ab string
-	for _, c := range header {
-		switch c.Name {
-		case "-http-session-":
-			session = c.Value
-		case "ABBCX":
-			session_ab = c.Value
-		default:
-			continue
-		}
-	}
-	requestHeader := http.Header{}
-	cookie1 := &http.Cookie{Name: "-http-session-", Value: session}
-	cookie2 := &http.Cookie{Name: "ABBCX", Value: session_ab}
-	requestHeader.Add("Cookie", cookie1.String()+"; "+cookie2.String())
-	requestHeader.Add("Origin", strings.Split(ws_url, "/poll")[0])
-	requestHeader.Add("Sec-WebSocket-Protocol", "robapi2_subscription")
-	conn, _, err := websocket.DefaultDialer.Dial(ws_url, requestHeader)
+	conn, err := c.subscribe("/rw/elog/1")
 	if err != nil {
 		return nil, err
 	}
-	defer conn.Close()
+	returnChannel := make(chan map[string]string)
 	go func() {
-		defer func() {
-			close(returnChannel)
-		}()
-		err = conn.SetReadDeadline(time.Now().Add(60 * time.Second))
-		if err != nil {
-			return
-		}
-		conn.SetPongHandler(func(string) error {
-			if err := conn.SetReadDeadline(time.Now().Add(60 * time.Second)); err != nil {
-				return err
-			}
-			return nil
-		})
+		defer close(returnChannel)
+		defer conn.Close()
 		for {
-			_, message, err := conn.ReadMessage()
+			_, raw, err := conn.ReadMessage()
 			if err != nil {
 				return
 			}
-			MessageXML := structures.ElogXML{}
-			err = xml.Unmarshal(message, &MessageXML)
+			var event structures.SubscriptionEventXML
+			err = xml.Unmarshal(raw, &event)
 			if err != nil {
 				return
 			}
-			endpoint := MessageXML.Body.Div.List.Endpoint.Href
-			msg, err := c.getElogMessages(endpoint)
-			if err != nil {
-				continue
-			}
-			mapString := make(map[string]string)
-			for _, m := range msg.Body.Div.List.Span {
-				mapString[m.Class] = m.Text
+			for _, li := range event.Body.Div.List {
+				domain, seqNum, ok := elogRef(li.Link.Href)
+				if !ok {
+					continue
+				}
+				message, err := c.getElogMessage(domain, seqNum, c.GetLanguage())
+				if err != nil {
+					continue
+				}
+				returnChannel <- elogEvent(*message)
 			}
-			returnChannel <- mapString
 		}
 	}()
 	return returnChannel, nil
 }
 
+// elogEvent converts a typed message into the event map of SubscribeToElog.
+func elogEvent(Message structures.ElogMessage) map[string]string {
+	event := map[string]string{
+		"domain":  strconv.Itoa(Message.Domain),
+		"seqnum":  strconv.Itoa(Message.SeqNum),
+		"msgtype": strconv.Itoa(Message.MsgType),
+		"code":    strconv.Itoa(Message.Code),
+		"tstamp":  Message.Timestamp.Format(rwsTimeLayout),
+		"title":   Message.Title,
+		"desc":    Message.Description,
+		"conseqs": Message.Consequences,
+		"causes":  Message.Causes,
+		"actions": Message.Actions,
+		"argc":    strconv.Itoa(len(Message.Args)),
+	}
+	for i, arg := range Message.Args {
+		event["arg"+strconv.Itoa(i+1)] = arg
+	}
+	return event
+}
+
 // ClearElogMessages clears all messages from the Elog system on domain 0.
 func (c *Client) ClearElogMessages() error {
 	c.Client = c.DigestAuthenticate()
@@ -177,3 +257,214 @@ func (c *Client) ClearElogMessages() error {
 	defer closeErrorCheck(resp.Body)
 	return nil
 }
+
+// GetElogMessage returns a single Elog message in the language of the client.
+// Example: Domain = 1, SeqNum = 34
+func (c *Client) GetElogMessage(Domain int, SeqNum int) (*structures.ElogMessage, error) {
+	return c.getElogMessage(Domain, SeqNum, c.GetLanguage())
+}
+
+// GetElogMessageTranslations returns the same Elog message in several languages by language,
+// e.g. for HMIs that show the message both in English and the local language.
+// Example: GetElogMessageTranslations(1, 34, "en", "de")
+func (c *Client) GetElogMessageTranslations(Domain int, SeqNum int, Languages ...string) (map[string]structures.ElogMessage, error) {
+	err := c.checkLanguages(Languages...)
+	if err != nil {
+		return nil, err
+	}
+	translations := make(map[string]structures.ElogMessage)
+	for _, language := range Languages {
+		message, err := c.getElogMessage(Domain, SeqNum, language)
+		if err != nil {
+			return nil, err
+		}
+		translations[language] = *message
+	}
+	return translations, nil
+}
+
+// getElogMessage is a helper function that returns a single typed Elog message in the given language.
+func (c *Client) getElogMessage(Domain int, SeqNum int, Language string) (*structures.ElogMessage, error) {
+	endpoint := "/rw/elog/" + strconv.Itoa(Domain) + "/" + strconv.Itoa(SeqNum)
+	messageRaw, err := c.getElogMessages(endpoint, Language)
+	if err != nil {
+		return nil, err
+	}
+	message := decodeElogMessage(Domain, endpoint, messageRaw.Body.Div.List.Span)
+	return &message, nil
+}
+
+// ListElogDomains returns the event log domains of the controller with their buffer sizes.
+func (c *Client) ListElogDomains() ([]structures.ElogDomain, error) {
+	var domainsRaw structures.ElogDomainsXML
+	err := c.getElogPage("http://"+c.Host+"/rw/elog?lang="+c.GetLanguage(), &domainsRaw)
+	if err != nil {
+		return nil, err
+	}
+	var domains []structures.ElogDomain
+	for _, li := range domainsRaw.Body.Div.List {
+		domain := structures.ElogDomain{}
+		domain.Number, err = strconv.Atoi(strings.TrimSpace(li.Title))
+		if err != nil {
+			return nil, fmt.Errorf("invalid elog domain: %s", li.Title)
+		}
+		for _, span := range li.Span {
+			text := strings.TrimSpace(span.Text)
+			switch span.Class {
+			case "domain-name":
+				domain.Name = text
+			case "buffsize":
+				domain.BufferSize, _ = strconv.Atoi(text)
+			case "numevts":
+				domain.Messages, _ = strconv.Atoi(text)
+			}
+		}
+		domains = append(domains, domain)
+	}
+	return domains, nil
+}
+
+// GetElogMessages returns the messages of an Elog domain that match the filter, newest first.
+// The pages of the domain are followed until the filter limit is reached or the messages are older than
+// Since or AfterSeqNum.
+// Example: GetElogMessages(1, structures.ElogFilter{Types: []int{structures.ElogError}, Limit: 50})
+func (c *Client) GetElogMessages(Domain int, Filter structures.ElogFilter) ([]structures.ElogMessage, error) {
+	var messages []structures.ElogMessage
+	next := "http://" + c.Host + "/rw/elog/" + strconv.Itoa(Domain) + "?lang=" + c.GetLanguage()
+	for next != "" {
+		var page structures.ElogDomainMessagesXML
+		err := c.getElogPage(next, &page)
+		if err != nil {
+			return nil, err
+		}
+		for _, li := range page.Body.Div.List {
+			message := decodeElogMessage(Domain, li.Title, li.Span)
+			if !Filter.Since.IsZero() && message.Timestamp.Before(Filter.Since) {
+				return messages, nil
+			}
+			if Filter.AfterSeqNum > 0 && message.SeqNum <= Filter.AfterSeqNum {
+				return messages, nil
+			}
+			if !elogMessageMatches(message, Filter) {
+				continue
+			}
+			messages = append(messages, message)
+			if Filter.Limit > 0 && len(messages) >= Filter.Limit {
+				return messages, nil
+			}
+		}
+		current := next
+		next = ""
+		for _, link := range page.Body.Div.Links {
+			if link.Rel == "next" {
+				next = nextPageURL(current, page.Head.BaseLink.Href, link.Href)
+			}
+		}
+	}
+	return messages, nil
+}
+
+// elogMessageMatches reports whether a message matches the types, codes and time window of a filter.
+func elogMessageMatches(Message structures.ElogMessage, Filter structures.ElogFilter) bool {
+	if len(Filter.Types) > 0 {
+		found := false
+		for _, msgType := range Filter.Types {
+			found = found || msgType == Message.MsgType
+		}
+		if !found {
+			return false
+		}
+	}
+	if len(Filter.Codes) > 0 {
+		found := false
+		for _, codes := range Filter.Codes {
+			found = found || (Message.Code >= codes.Min && Message.Code <= codes.Max)
+		}
+		if !found {
+			return false
+		}
+	}
+	if !Filter.Since.IsZero() && Message.Timestamp.Before(Filter.Since) {
+		return false
+	}
+	if !Filter.Until.IsZero() && Message.Timestamp.After(Filter.Until) {
+		return false
+	}
+	return true
+}
+
+// getElogPage is a helper function that decodes a single XML page of the Elog resources.
+func (c *Client) getElogPage(URL string, Page interface{}) error {
+	c.Client = c.DigestAuthenticate()
+	req, err := http.NewRequest("GET", URL, nil)
+	if err != nil {
+		return err
+	}
+	resp, err := c.Client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer closeErrorCheck(resp.Body)
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("HTTP Status Code: %d", resp.StatusCode)
+	}
+	return xml.NewDecoder(resp.Body).Decode(Page)
+}
+
+// elogSpanClasses are the classes of the spans of an Elog message, apart from the arguments, in document order.
+var elogSpanClasses = []string{"msgtype", "code", "tstamp", "title", "desc", "conseqs", "causes", "actions"}
+
+// DecodeElogEvent converts an event of SubscribeToElog into a typed message.
+func DecodeElogEvent(Event map[string]string) structures.ElogMessage {
+	domain, _ := strconv.Atoi(Event["domain"])
+	spans := make([]structures.ElogMessageSpan, 0, len(Event))
+	// the spans are built in a fixed order, the iteration order of the event map is random
+	for _, class := range elogSpanClasses {
+		if text, ok := Event[class]; ok {
+			spans = append(spans, structures.ElogMessageSpan{Class: class, Text: text})
+		}
+	}
+	argc, _ := strconv.Atoi(Event["argc"])
+	for i := 1; i <= argc; i++ {
+		class := "arg" + strconv.Itoa(i)
+		spans = append(spans, structures.ElogMessageSpan{Class: class, Text: Event[class]})
+	}
+	return decodeElogMessage(domain, Event["seqnum"], spans)
+}
+
+// decodeElogMessage converts the spans of an Elog message into a typed message.
+// Ref is the message title or href, the sequence number is its last path element.
+func decodeElogMessage(Domain int, Ref string, Spans []structures.ElogMessageSpan) structures.ElogMessage {
+	message := structures.ElogMessage{Domain: Domain}
+	ref := strings.TrimSuffix(strings.Split(Ref, "?")[0], "/")
+	if i := strings.LastIndex(ref, "/"); i >= 0 {
+		ref = ref[i+1:]
+	}
+	message.SeqNum, _ = strconv.Atoi(ref)
+	for _, span := range Spans {
+		text := strings.TrimSpace(span.Text)
+		switch span.Class {
+		case "msgtype":
+			message.MsgType, _ = strconv.Atoi(text)
+		case "code":
+			message.Code, _ = strconv.Atoi(text)
+		case "tstamp":
+			message.Timestamp, _ = parseRWSTime(text)
+		case "title":
+			message.Title = text
+		case "desc":
+			message.Description = text
+		case "conseqs":
+			message.Consequences = text
+		case "causes":
+			message.Causes = text
+		case "actions":
+			message.Actions = text
+		default:
+			if strings.HasPrefix(span.Class, "arg") && span.Class != "argc" {
+				message.Args = append(message.Args, text)
+			}
+		}
+	}
+	return message
+}
@@ -0,0 +1,198 @@
+package abb
+
+import (
+	"context"
+	"encoding/xml"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/atmassey/abb-lib-rws/structures"
+	"github.com/gorilla/websocket"
+)
+
+// maxElogReconnectDelay is the longest wait between two reconnect attempts of an Elog stream.
+const maxElogReconnectDelay = 30 * time.Second
+
+// SubscribeToElogMessages streams the typed messages of the given Elog domains until the context is done.
+// The stream tracks the position of the last message per domain. When the websocket drops it reconnects
+// and backfills the messages it missed from the history, duplicates are suppressed. A cleared Elog or
+// wrapped sequence numbers are detected by the message timestamps, see structures.ElogPosition.
+// Last is the last message per domain already processed, e.g. the Last of an elogexport.Cursor from a
+// previous run, nil starts with new messages only. An error is returned only when the first connection fails.
+// Example: SubscribeToElogMessages(ctx, nil, 0, 1)
+func (c *Client) SubscribeToElogMessages(Ctx context.Context, Last map[int]structures.ElogPosition, Domains ...int) (chan structures.ElogMessage, error) {
+	if len(Domains) == 0 {
+		Domains = []int{1}
+	}
+	stream := elogStream{client: c, domains: Domains, last: make(map[int]structures.ElogPosition), messages: make(chan structures.ElogMessage)}
+	for domain, position := range Last {
+		stream.last[domain] = position
+	}
+	conn, err := stream.connect()
+	if err != nil {
+		return nil, err
+	}
+	go stream.run(Ctx, conn)
+	return stream.messages, nil
+}
+
+// elogStream is the state of a stream created by SubscribeToElogMessages.
+type elogStream struct {
+	client   *Client
+	domains  []int
+	last     map[int]structures.ElogPosition
+	messages chan structures.ElogMessage
+}
+
+// connect subscribes to the domains. Domains without a last position start at their newest message.
+func (s *elogStream) connect() (*websocket.Conn, error) {
+	resources := make([]string, 0, len(s.domains))
+	for _, domain := range s.domains {
+		resources = append(resources, "/rw/elog/"+strconv.Itoa(domain))
+	}
+	conn, err := s.client.subscribe(resources...)
+	if err != nil {
+		return nil, err
+	}
+	for _, domain := range s.domains {
+		if _, ok := s.last[domain]; ok {
+			continue
+		}
+		newest, err := s.client.GetElogMessages(domain, structures.ElogFilter{Limit: 1})
+		if err != nil {
+			conn.Close()
+			return nil, err
+		}
+		s.last[domain] = structures.ElogPosition{}
+		if len(newest) > 0 {
+			s.last[domain] = structures.ElogPosition{SeqNum: newest[0].SeqNum, Time: newest[0].Timestamp}
+		}
+	}
+	return conn, nil
+}
+
+// backfill delivers the messages after the last position of every domain, oldest first. Positions with
+// a timestamp look up the history by time, so messages logged after a clear are found as well.
+func (s *elogStream) backfill(Ctx context.Context) error {
+	for _, domain := range s.domains {
+		filter := structures.ElogFilter{AfterSeqNum: s.last[domain].SeqNum}
+		if !s.last[domain].Time.IsZero() {
+			filter = structures.ElogFilter{Since: s.last[domain].Time}
+		}
+		missed, err := s.client.GetElogMessages(domain, filter)
+		if err != nil {
+			return err
+		}
+		// history is newest first
+		for i := len(missed) - 1; i >= 0; i-- {
+			if !s.deliver(Ctx, missed[i]) {
+				return Ctx.Err()
+			}
+		}
+	}
+	return nil
+}
+
+// run backfills and reads the websocket events. When the connection drops it reconnects with an
+// increasing delay until the context is done.
+func (s *elogStream) run(Ctx context.Context, Conn *websocket.Conn) {
+	defer close(s.messages)
+	delay := time.Second
+	for {
+		if Conn != nil {
+			if s.backfill(Ctx) == nil {
+				s.read(Ctx, Conn)
+			}
+			Conn.Close()
+			Conn = nil
+		}
+		select {
+		case <-Ctx.Done():
+			return
+		case <-time.After(delay):
+		}
+		conn, err := s.connect()
+		if err != nil {
+			delay = min(delay*2, maxElogReconnectDelay)
+			continue
+		}
+		Conn = conn
+		delay = time.Second
+	}
+}
+
+// read delivers the messages of the websocket events until the connection fails, a message can't be
+// fetched or the context is done.
+func (s *elogStream) read(Ctx context.Context, Conn *websocket.Conn) {
+	stop := make(chan struct{})
+	defer close(stop)
+	go func() {
+		select {
+		case <-Ctx.Done():
+			Conn.Close()
+		case <-stop:
+		}
+	}()
+	for {
+		_, raw, err := Conn.ReadMessage()
+		if err != nil {
+			return
+		}
+		var event structures.SubscriptionEventXML
+		err = xml.Unmarshal(raw, &event)
+		if err != nil {
+			continue
+		}
+		for _, li := range event.Body.Div.List {
+			domain, seqNum, ok := elogRef(li.Link.Href)
+			if !ok {
+				continue
+			}
+			// a lower sequence number can be new after a clear, only its timestamp tells
+			if last := s.last[domain]; seqNum <= last.SeqNum && last.Time.IsZero() {
+				continue
+			}
+			message, err := s.client.getElogMessage(domain, seqNum, s.client.GetLanguage())
+			if err != nil {
+				// reconnect and backfill from the last delivered message so nothing after the gap is delivered first
+				return
+			}
+			if !s.deliver(Ctx, *message) {
+				return
+			}
+		}
+	}
+}
+
+// deliver sends a message that was not delivered before and records its position.
+// It returns false when the context is done.
+func (s *elogStream) deliver(Ctx context.Context, Message structures.ElogMessage) bool {
+	if s.last[Message.Domain].Covers(Message) {
+		return true
+	}
+	select {
+	case s.messages <- Message:
+		s.last[Message.Domain] = structures.ElogPosition{SeqNum: Message.SeqNum, Time: Message.Timestamp}
+		return true
+	case <-Ctx.Done():
+		return false
+	}
+}
+
+// elogRef returns the domain and sequence number of an Elog message href, e.g. /rw/elog/1/34?lang=en.
+func elogRef(Href string) (int, int, bool) {
+	parts := strings.Split(strings.Trim(strings.Split(Href, "?")[0], "/"), "/")
+	if len(parts) < 2 {
+		return 0, 0, false
+	}
+	domain, err := strconv.Atoi(parts[len(parts)-2])
+	if err != nil {
+		return 0, 0, false
+	}
+	seqNum, err := strconv.Atoi(parts[len(parts)-1])
+	if err != nil {
+		return 0, 0, false
+	}
+	return domain, seqNum, true
+}
@@ -0,0 +1,78 @@
+package elogexport
+
+import (
+	"encoding/json"
+	"errors"
+	"os"
+	"path/filepath"
+
+	"github.com/atmassey/abb-lib-rws/structures"
+)
+
+// Cursor is the last exported message per Elog domain, persisted as JSON.
+type Cursor struct {
+	Path string
+	// Last maps a domain to the last exported message.
+	Last map[int]structures.ElogPosition
+}
+
+// LoadCursor reads a cursor from a file, a missing file returns an empty cursor.
+func LoadCursor(Path string) (*Cursor, error) {
+	cursor := Cursor{Path: Path, Last: make(map[int]structures.ElogPosition)}
+	data, err := os.ReadFile(Path)
+	if errors.Is(err, os.ErrNotExist) {
+		return &cursor, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	err = json.Unmarshal(data, &cursor.Last)
+	if err != nil {
+		// cursors written by older versions only hold the sequence numbers
+		var seqNums map[int]int
+		if json.Unmarshal(data, &seqNums) != nil {
+			return nil, err
+		}
+		for domain, seqNum := range seqNums {
+			cursor.Last[domain] = structures.ElogPosition{SeqNum: seqNum}
+		}
+	}
+	return &cursor, nil
+}
+
+// Seen reports whether a message was already exported, see structures.ElogPosition.Covers.
+func (c *Cursor) Seen(Message structures.ElogMessage) bool {
+	last, ok := c.Last[Message.Domain]
+	return ok && last.Covers(Message)
+}
+
+// Advance records a message as exported, the cursor is reset when the message is new.
+func (c *Cursor) Advance(Message structures.ElogMessage) {
+	if !c.Seen(Message) {
+		c.Last[Message.Domain] = structures.ElogPosition{SeqNum: Message.SeqNum, Time: Message.Timestamp}
+	}
+}
+
+// Save writes the cursor to its file. The file is replaced atomically so a crash can't corrupt it.
+func (c *Cursor) Save() error {
+	if c.Path == "" {
+		return nil
+	}
+	data, err := json.Marshal(c.Last)
+	if err != nil {
+		return err
+	}
+	tmp, err := os.CreateTemp(filepath.Dir(c.Path), filepath.Base(c.Path)+".*")
+	if err != nil {
+		return err
+	}
+	_, err = tmp.Write(data)
+	if closeErr := tmp.Close(); err == nil {
+		err = closeErr
+	}
+	if err != nil {
+		os.Remove(tmp.Name())
+		return err
+	}
+	return os.Rename(tmp.Name(), c.Path)
+}
@@ -0,0 +1,156 @@
+// Package elogexport writes typed Elog messages to central logging, as CSV, JSON Lines or RFC 5424 syslog.
+// Messages can come from the history (GetElogMessages) or a live stream (SubscribeToElogMessages,
+// or SubscribeToElog with DecodeElogEvent). A Cursor persists the sequence number and timestamp of the last exported message per domain so messages
+// are not exported twice across restarts.
+package elogexport
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"io"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/atmassey/abb-lib-rws/structures"
+)
+
+// Exporter writes Elog messages to a destination.
+type Exporter interface {
+	Export(Message structures.ElogMessage) error
+	// Flush writes any buffered messages.
+	Flush() error
+}
+
+// Export writes the messages that the cursor has not seen yet in time and sequence order per domain,
+// advances the cursor and saves it. A nil cursor exports every message.
+func Export(Exporter Exporter, Cursor *Cursor, Messages []structures.ElogMessage) error {
+	messages := append([]structures.ElogMessage(nil), Messages...)
+	// time order keeps messages logged before a clear of the Elog ahead of the renumbered ones
+	sort.SliceStable(messages, func(i, j int) bool {
+		if messages[i].Domain != messages[j].Domain {
+			return messages[i].Domain < messages[j].Domain
+		}
+		if !messages[i].Timestamp.Equal(messages[j].Timestamp) {
+			return messages[i].Timestamp.Before(messages[j].Timestamp)
+		}
+		return messages[i].SeqNum < messages[j].SeqNum
+	})
+	for _, message := range messages {
+		if Cursor != nil && Cursor.Seen(message) {
+			continue
+		}
+		err := Exporter.Export(message)
+		if err != nil {
+			return err
+		}
+		if Cursor != nil {
+			Cursor.Advance(message)
+		}
+	}
+	err := Exporter.Flush()
+	if err != nil {
+		return err
+	}
+	if Cursor != nil {
+		return Cursor.Save()
+	}
+	return nil
+}
+
+// ExportStream writes messages from a live stream until the channel is closed. The exporter is
+// flushed and the cursor saved after every message.
+func ExportStream(Exporter Exporter, Cursor *Cursor, Messages <-chan structures.ElogMessage) error {
+	for message := range Messages {
+		err := Export(Exporter, Cursor, []structures.ElogMessage{message})
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// CSV writes one row per message with a header row.
+type CSV struct {
+	w      *csv.Writer
+	header bool
+}
+
+// NewCSV creates a CSV exporter writing to W.
+func NewCSV(W io.Writer) *CSV {
+	return &CSV{w: csv.NewWriter(W)}
+}
+
+func (e *CSV) Export(Message structures.ElogMessage) error {
+	if !e.header {
+		e.header = true
+		err := e.w.Write([]string{"domain", "seqnum", "timestamp", "type", "code", "title", "description", "consequences", "causes", "actions", "args"})
+		if err != nil {
+			return err
+		}
+	}
+	return e.w.Write([]string{
+		strconv.Itoa(Message.Domain),
+		strconv.Itoa(Message.SeqNum),
+		Message.Timestamp.Format(time.RFC3339),
+		structures.ElogTypeName(Message.MsgType),
+		strconv.Itoa(Message.Code),
+		Message.Title,
+		Message.Description,
+		Message.Consequences,
+		Message.Causes,
+		Message.Actions,
+		strings.Join(Message.Args, ";"),
+	})
+}
+
+func (e *CSV) Flush() error {
+	e.w.Flush()
+	return e.w.Error()
+}
+
+// JSONLines writes one JSON object per line.
+type JSONLines struct {
+	enc *json.Encoder
+}
+
+// NewJSONLines creates a JSON Lines exporter writing to W.
+func NewJSONLines(W io.Writer) *JSONLines {
+	return &JSONLines{enc: json.NewEncoder(W)}
+}
+
+// jsonMessage is the JSON Lines representation of a message.
+type jsonMessage struct {
+	Domain       int       `json:"domain"`
+	SeqNum       int       `json:"seqnum"`
+	Timestamp    time.Time `json:"timestamp"`
+	Type         string    `json:"type"`
+	Code         int       `json:"code"`
+	Title        string    `json:"title"`
+	Description  string    `json:"description,omitempty"`
+	Consequences string    `json:"consequences,omitempty"`
+	Causes       string    `json:"causes,omitempty"`
+	Actions      string    `json:"actions,omitempty"`
+	Args         []string  `json:"args,omitempty"`
+}
+
+func (e *JSONLines) Export(Message structures.ElogMessage) error {
+	return e.enc.Encode(jsonMessage{
+		Domain:       Message.Domain,
+		SeqNum:       Message.SeqNum,
+		Timestamp:    Message.Timestamp,
+		Type:         structures.ElogTypeName(Message.MsgType),
+		Code:         Message.Code,
+		Title:        Message.Title,
+		Description:  Message.Description,
+		Consequences: Message.Consequences,
+		Causes:       Message.Causes,
+		Actions:      Message.Actions,
+		Args:         Message.Args,
+	})
+}
+
+func (e *JSONLines) Flush() error {
+	return nil
+}
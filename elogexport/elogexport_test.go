@@ -0,0 +1,102 @@
+package elogexport
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/atmassey/abb-lib-rws/structures"
+)
+
+var messages = []structures.ElogMessage{
+	{Domain: 1, SeqNum: 35, MsgType: structures.ElogInfo, Code: 10011, Timestamp: time.Date(2024, 7, 16, 12, 1, 0, 0, time.UTC), Title: "Motors ON state"},
+	{Domain: 1, SeqNum: 34, MsgType: structures.ElogError, Code: 50204, Timestamp: time.Date(2024, 7, 16, 12, 0, 0, 0, time.UTC), Title: "Motion supervision", Args: []string{"ROB_1", `"3"`}},
+}
+
+func TestSyslogFormat(t *testing.T) {
+	syslog := NewSyslog(nil, "IRB 120")
+	line := syslog.Format(messages[1])
+	expected := `<131>1 2024-07-16T12:00:00Z IRB_120 abb-elog - 50204 [elog@32473 domain="1" seqnum="34" type="error" code="50204" arg1="ROB_1" arg2="\"3\""] Motion supervision`
+	if line != expected {
+		t.Errorf("unexpected syslog message:\n%s", line)
+	}
+}
+
+func TestExportCursor(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "cursor.json")
+	cursor, err := LoadCursor(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var out bytes.Buffer
+	err = Export(NewJSONLines(&out), cursor, messages)
+	if err != nil {
+		t.Fatal(err)
+	}
+	lines := strings.Split(strings.TrimSpace(out.String()), "\n")
+	if len(lines) != 2 || !strings.Contains(lines[0], `"seqnum":34`) {
+		t.Fatalf("unexpected output: %s", out.String())
+	}
+	// a restarted exporter must not export the same messages again
+	cursor, err = LoadCursor(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	out.Reset()
+	err = Export(NewCSV(&out), cursor, append(messages, structures.ElogMessage{Domain: 1, SeqNum: 36, Timestamp: time.Date(2024, 7, 16, 12, 2, 0, 0, time.UTC), Title: "new"}))
+	if err != nil {
+		t.Fatal(err)
+	}
+	rows := strings.Split(strings.TrimSpace(out.String()), "\n")
+	if len(rows) != 2 || !strings.HasPrefix(rows[1], "1,36,") {
+		t.Errorf("unexpected CSV: %s", out.String())
+	}
+}
+
+func TestExportCursorAfterClear(t *testing.T) {
+	cursor, err := LoadCursor(filepath.Join(t.TempDir(), "cursor.json"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	var out bytes.Buffer
+	if err := Export(NewJSONLines(&out), cursor, messages); err != nil {
+		t.Fatal(err)
+	}
+	// the Elog was cleared, the new messages start again at 1 while the old ones are still delivered
+	cleared := []structures.ElogMessage{
+		messages[0],
+		{Domain: 1, SeqNum: 1, Timestamp: time.Date(2024, 7, 16, 13, 0, 0, 0, time.UTC), Title: "Elog cleared"},
+		{Domain: 1, SeqNum: 2, Timestamp: time.Date(2024, 7, 16, 13, 1, 0, 0, time.UTC), Title: "Motors ON state"},
+	}
+	out.Reset()
+	if err := Export(NewJSONLines(&out), cursor, cleared); err != nil {
+		t.Fatal(err)
+	}
+	lines := strings.Split(strings.TrimSpace(out.String()), "\n")
+	if len(lines) != 2 || !strings.Contains(lines[0], `"seqnum":1`) || !strings.Contains(lines[1], `"seqnum":2`) {
+		t.Fatalf("unexpected output: %s", out.String())
+	}
+	if last := cursor.Last[1]; last.SeqNum != 2 || !last.Time.Equal(cleared[2].Timestamp) {
+		t.Errorf("unexpected cursor: %+v", last)
+	}
+	if !cursor.Seen(cleared[1]) || !cursor.Seen(messages[0]) {
+		t.Error("expected the exported messages to be seen")
+	}
+}
+
+func TestLoadLegacyCursor(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "cursor.json")
+	if err := os.WriteFile(path, []byte(`{"1":35}`), 0644); err != nil {
+		t.Fatal(err)
+	}
+	cursor, err := LoadCursor(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !cursor.Seen(messages[0]) || cursor.Seen(structures.ElogMessage{Domain: 1, SeqNum: 36}) {
+		t.Errorf("unexpected cursor: %+v", cursor.Last)
+	}
+}
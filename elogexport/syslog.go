@@ -0,0 +1,133 @@
+package elogexport
+
+import (
+	"fmt"
+	"io"
+	"net"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/atmassey/abb-lib-rws/structures"
+)
+
+// Syslog facilities, see RFC 5424 section 6.2.1.
+const (
+	FacilityUser   = 1
+	FacilityLocal0 = 16
+)
+
+// sdID is the structured data ID of the elog parameters, using the enterprise number reserved for documentation.
+const sdID = "elog@32473"
+
+// Syslog writes RFC 5424 messages. Over UDP every message is sent as its own datagram, over TCP
+// messages are framed with octet counting (RFC 6587) and on a writer they are separated by newlines.
+type Syslog struct {
+	// Hostname identifies the robot controller, e.g. its host name or system name.
+	Hostname string
+	AppName  string
+	Facility int
+
+	w       io.Writer
+	network string
+}
+
+// NewSyslog creates a syslog exporter writing newline separated messages to W.
+func NewSyslog(W io.Writer, Hostname string) *Syslog {
+	return &Syslog{Hostname: Hostname, AppName: "abb-elog", Facility: FacilityLocal0, w: W}
+}
+
+// DialSyslog creates a syslog exporter sending to a syslog server.
+// Example: Network = udp, Address = logs.example.com:514
+func DialSyslog(Network string, Address string, Hostname string) (*Syslog, error) {
+	if Network != "udp" && Network != "tcp" {
+		return nil, fmt.Errorf("unsupported syslog network: %s", Network)
+	}
+	conn, err := net.Dial(Network, Address)
+	if err != nil {
+		return nil, err
+	}
+	syslog := NewSyslog(conn, Hostname)
+	syslog.network = Network
+	return syslog, nil
+}
+
+// Severity maps an Elog message type to a syslog severity.
+func Severity(MsgType int) int {
+	switch MsgType {
+	case structures.ElogError:
+		return 3
+	case structures.ElogWarning:
+		return 4
+	case structures.ElogInfo:
+		return 6
+	default:
+		return 5
+	}
+}
+
+func (e *Syslog) Export(Message structures.ElogMessage) error {
+	line := e.Format(Message)
+	var err error
+	switch e.network {
+	case "udp":
+		_, err = io.WriteString(e.w, line)
+	case "tcp":
+		_, err = io.WriteString(e.w, strconv.Itoa(len(line))+" "+line)
+	default:
+		_, err = io.WriteString(e.w, line+"\n")
+	}
+	return err
+}
+
+// Format returns the RFC 5424 representation of a message.
+func (e *Syslog) Format(Message structures.ElogMessage) string {
+	timestamp := "-"
+	if !Message.Timestamp.IsZero() {
+		timestamp = Message.Timestamp.Format(time.RFC3339)
+	}
+	var sd strings.Builder
+	fmt.Fprintf(&sd, `[%s domain="%d" seqnum="%d" type="%s" code="%d"`, sdID, Message.Domain, Message.SeqNum, structures.ElogTypeName(Message.MsgType), Message.Code)
+	for i, arg := range Message.Args {
+		fmt.Fprintf(&sd, ` arg%d="%s"`, i+1, escapeParam(arg))
+	}
+	sd.WriteString("]")
+	text := Message.Title
+	if Message.Description != "" {
+		text += ": " + Message.Description
+	}
+	return fmt.Sprintf("<%d>1 %s %s %s - %d %s %s",
+		e.Facility*8+Severity(Message.MsgType),
+		timestamp,
+		headerField(e.Hostname),
+		headerField(e.AppName),
+		Message.Code,
+		sd.String(),
+		strings.Join(strings.Fields(text), " "),
+	)
+}
+
+func (e *Syslog) Flush() error {
+	return nil
+}
+
+// Close closes the connection to the syslog server, if any.
+func (e *Syslog) Close() error {
+	if closer, ok := e.w.(io.Closer); ok && e.network != "" {
+		return closer.Close()
+	}
+	return nil
+}
+
+// headerField returns a header field without spaces, or - if it is empty.
+func headerField(Value string) string {
+	if Value == "" {
+		return "-"
+	}
+	return strings.ReplaceAll(Value, " ", "_")
+}
+
+// escapeParam escapes a structured data parameter value, see RFC 5424 section 6.3.3.
+func escapeParam(Value string) string {
+	return strings.NewReplacer(`\`, `\\`, `"`, `\"`, `]`, `\]`).Replace(Value)
+}
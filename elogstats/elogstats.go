@@ -0,0 +1,224 @@
+// Package elogstats analyzes Elog messages to find recurring faults: counts per code and category,
+// time between occurrences, MTBF per error code and bursts, summarized in a ranked report.
+// Messages come from the controller history (LoadHistory) or any other source of typed messages.
+// Offline analysis of dumps saved with SaveElogSystemDump is not supported, their format is not documented.
+package elogstats
+
+import (
+	"sort"
+	"time"
+
+	"github.com/atmassey/abb-lib-rws/structures"
+)
+
+// categories are the Elog categories by the leading digits of the code, e.g. 50204 -> Motion.
+var categories = map[int]string{
+	1:  "Operational",
+	2:  "System",
+	3:  "Hardware",
+	4:  "Program",
+	5:  "Motion",
+	6:  "Operator",
+	7:  "IO & Communication",
+	8:  "User",
+	9:  "Safety",
+	10: "Internal",
+	11: "Process",
+	12: "Configuration",
+	13: "Paint",
+	15: "RAPID",
+	17: "Connected Services",
+}
+
+// Category returns the category of an Elog code, e.g. 50204 -> Motion.
+func Category(Code int) string {
+	if name, ok := categories[Code/10000]; ok {
+		return name
+	}
+	return "Other"
+}
+
+// Options tune the analysis. Zero values use the defaults.
+type Options struct {
+	// BurstWindow is the time window in which BurstSize occurrences of a code form a burst, default 1 minute.
+	BurstWindow time.Duration
+	// BurstSize is the number of occurrences within BurstWindow that form a burst, default 5.
+	BurstSize int
+}
+
+// CodeStats are the statistics of a single Elog code.
+type CodeStats struct {
+	Code     int
+	Title    string
+	Category string
+	MsgType  int
+	Count    int
+	First    time.Time
+	Last     time.Time
+	// MeanInterval, MinInterval and MaxInterval are the times between consecutive occurrences.
+	MeanInterval time.Duration
+	MinInterval  time.Duration
+	MaxInterval  time.Duration
+	// MTBF is the observed period divided by the number of occurrences, only set for errors.
+	// It is 0 when the observed period is 0, e.g. for a single message.
+	MTBF time.Duration
+}
+
+// CategoryStats are the message counts of a category.
+type CategoryStats struct {
+	Category string
+	Count    int
+	Errors   int
+	Warnings int
+}
+
+// Burst is a series of at least BurstSize occurrences of a code where each occurrence is within
+// BurstWindow of the BurstSize-1 occurrences before it.
+type Burst struct {
+	Code  int
+	Start time.Time
+	End   time.Time
+	Count int
+}
+
+// Report is the result of an analysis. Codes are ranked by severity and count, categories by count
+// and bursts by size.
+type Report struct {
+	From       time.Time
+	To         time.Time
+	Total      int
+	Codes      []CodeStats
+	Categories []CategoryStats
+	Bursts     []Burst
+}
+
+// Analyze computes the statistics of a set of messages, in any order.
+func Analyze(Messages []structures.ElogMessage, Options Options) *Report {
+	if Options.BurstWindow <= 0 {
+		Options.BurstWindow = time.Minute
+	}
+	if Options.BurstSize <= 0 {
+		Options.BurstSize = 5
+	}
+	messages := append([]structures.ElogMessage(nil), Messages...)
+	sort.SliceStable(messages, func(i, j int) bool {
+		return messages[i].Timestamp.Before(messages[j].Timestamp)
+	})
+	report := Report{Total: len(messages)}
+	if len(messages) == 0 {
+		return &report
+	}
+	report.From = messages[0].Timestamp
+	report.To = messages[len(messages)-1].Timestamp
+	byCode := make(map[int][]structures.ElogMessage)
+	byCategory := make(map[string]*CategoryStats)
+	for _, message := range messages {
+		byCode[message.Code] = append(byCode[message.Code], message)
+		category := Category(message.Code)
+		stats := byCategory[category]
+		if stats == nil {
+			stats = &CategoryStats{Category: category}
+			byCategory[category] = stats
+		}
+		stats.Count++
+		switch message.MsgType {
+		case structures.ElogError:
+			stats.Errors++
+		case structures.ElogWarning:
+			stats.Warnings++
+		}
+	}
+	period := report.To.Sub(report.From)
+	for code, occurrences := range byCode {
+		stats := codeStats(code, occurrences, period)
+		report.Codes = append(report.Codes, stats)
+		report.Bursts = append(report.Bursts, bursts(code, occurrences, Options)...)
+	}
+	for _, stats := range byCategory {
+		report.Categories = append(report.Categories, *stats)
+	}
+	sort.Slice(report.Codes, func(i, j int) bool {
+		a, b := report.Codes[i], report.Codes[j]
+		if a.MsgType != b.MsgType {
+			return a.MsgType > b.MsgType
+		}
+		if a.Count != b.Count {
+			return a.Count > b.Count
+		}
+		return a.Code < b.Code
+	})
+	sort.Slice(report.Categories, func(i, j int) bool {
+		a, b := report.Categories[i], report.Categories[j]
+		if a.Count != b.Count {
+			return a.Count > b.Count
+		}
+		return a.Category < b.Category
+	})
+	sort.Slice(report.Bursts, func(i, j int) bool {
+		a, b := report.Bursts[i], report.Bursts[j]
+		if a.Count != b.Count {
+			return a.Count > b.Count
+		}
+		return a.Start.Before(b.Start)
+	})
+	return &report
+}
+
+// codeStats computes the statistics of the occurrences of a code, sorted by time.
+func codeStats(Code int, Occurrences []structures.ElogMessage, Period time.Duration) CodeStats {
+	last := Occurrences[len(Occurrences)-1]
+	stats := CodeStats{
+		Code:     Code,
+		Title:    last.Title,
+		Category: Category(Code),
+		MsgType:  last.MsgType,
+		Count:    len(Occurrences),
+		First:    Occurrences[0].Timestamp,
+		Last:     last.Timestamp,
+	}
+	for i := 1; i < len(Occurrences); i++ {
+		interval := Occurrences[i].Timestamp.Sub(Occurrences[i-1].Timestamp)
+		if i == 1 || interval < stats.MinInterval {
+			stats.MinInterval = interval
+		}
+		if interval > stats.MaxInterval {
+			stats.MaxInterval = interval
+		}
+	}
+	if len(Occurrences) > 1 {
+		stats.MeanInterval = stats.Last.Sub(stats.First) / time.Duration(len(Occurrences)-1)
+	}
+	if stats.MsgType == structures.ElogError {
+		stats.MTBF = Period / time.Duration(len(Occurrences))
+	}
+	return stats
+}
+
+// bursts finds the bursts in the occurrences of a code, sorted by time. Overlapping windows are merged.
+func bursts(Code int, Occurrences []structures.ElogMessage, Options Options) []Burst {
+	var found []Burst
+	for i := Options.BurstSize - 1; i < len(Occurrences); i++ {
+		start := Occurrences[i-Options.BurstSize+1].Timestamp
+		end := Occurrences[i].Timestamp
+		if end.Sub(start) > Options.BurstWindow {
+			continue
+		}
+		if n := len(found); n > 0 && !start.After(found[n-1].End) {
+			found[n-1].End = end
+			found[n-1].Count = countBetween(Occurrences, found[n-1].Start, end)
+			continue
+		}
+		found = append(found, Burst{Code: Code, Start: start, End: end, Count: Options.BurstSize})
+	}
+	return found
+}
+
+func countBetween(Occurrences []structures.ElogMessage, Start time.Time, End time.Time) int {
+	count := 0
+	for _, occurrence := range Occurrences {
+		if !occurrence.Timestamp.Before(Start) && !occurrence.Timestamp.After(End) {
+			count++
+		}
+	}
+	return count
+}
@@ -0,0 +1,55 @@
+package elogstats
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/atmassey/abb-lib-rws/structures"
+)
+
+func TestSummarySingleError(t *testing.T) {
+	report := Analyze([]structures.ElogMessage{{Code: 50204, MsgType: structures.ElogError, Timestamp: time.Now()}}, Options{})
+	var summary bytes.Buffer
+	if err := WriteSummary(&summary, report, 0); err != nil {
+		t.Fatal(err)
+	}
+	// Rank Code Type Count MTBF ...
+	row := strings.Fields(strings.Split(summary.String(), "\n")[3])
+	if len(row) < 5 || row[1] != "50204" || row[4] != "n/a" {
+		t.Errorf("expected an unknown MTBF:\n%s", summary.String())
+	}
+}
+
+func TestAnalyze(t *testing.T) {
+	start := time.Date(2024, 7, 16, 12, 0, 0, 0, time.UTC)
+	var messages []structures.ElogMessage
+	// a burst of 6 collisions within a minute, then one more an hour later
+	for i := 0; i < 6; i++ {
+		messages = append(messages, structures.ElogMessage{Code: 50204, MsgType: structures.ElogError, Timestamp: start.Add(time.Duration(i) * 10 * time.Second)})
+	}
+	messages = append(messages, structures.ElogMessage{Code: 50204, MsgType: structures.ElogError, Timestamp: start.Add(time.Hour)})
+	messages = append(messages, structures.ElogMessage{Code: 10011, MsgType: structures.ElogInfo, Timestamp: start.Add(2 * time.Hour)})
+	report := Analyze(messages, Options{})
+	if report.Total != 8 || len(report.Codes) != 2 {
+		t.Fatalf("unexpected report: %+v", report)
+	}
+	top := report.Codes[0]
+	if top.Code != 50204 || top.Count != 7 || top.Category != "Motion" || top.MTBF != 2*time.Hour/7 {
+		t.Errorf("unexpected code stats: %+v", top)
+	}
+	if top.MinInterval != 10*time.Second || top.MaxInterval != time.Hour-50*time.Second {
+		t.Errorf("unexpected intervals: %+v", top)
+	}
+	if len(report.Bursts) != 1 || report.Bursts[0].Count != 6 {
+		t.Errorf("unexpected bursts: %+v", report.Bursts)
+	}
+	var summary bytes.Buffer
+	if err := WriteSummary(&summary, report, 1); err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(summary.String(), "50204") || strings.Contains(summary.String(), "Motors ON") {
+		t.Errorf("unexpected summary:\n%s", summary.String())
+	}
+}
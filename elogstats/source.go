@@ -0,0 +1,23 @@
+package elogstats
+
+import (
+	abb "github.com/atmassey/abb-lib-rws"
+	"github.com/atmassey/abb-lib-rws/structures"
+)
+
+// LoadHistory returns the messages of every Elog domain of the controller that match the filter.
+func LoadHistory(Client *abb.Client, Filter structures.ElogFilter) ([]structures.ElogMessage, error) {
+	domains, err := Client.ListElogDomains()
+	if err != nil {
+		return nil, err
+	}
+	var messages []structures.ElogMessage
+	for _, domain := range domains {
+		domainMessages, err := Client.GetElogMessages(domain.Number, Filter)
+		if err != nil {
+			return nil, err
+		}
+		messages = append(messages, domainMessages...)
+	}
+	return messages, nil
+}
@@ -0,0 +1,70 @@
+package elogstats
+
+import (
+	"fmt"
+	"io"
+	"text/tabwriter"
+	"time"
+
+	"github.com/atmassey/abb-lib-rws/structures"
+)
+
+// WriteSummary writes a ranked text summary of a report. Top limits the number of codes and
+// bursts listed, 0 lists all of them.
+func WriteSummary(W io.Writer, Report *Report, Top int) error {
+	tw := tabwriter.NewWriter(W, 0, 4, 2, ' ', 0)
+	fmt.Fprintf(tw, "Elog summary: %d messages from %s to %s\n\n", Report.Total, formatTime(Report.From), formatTime(Report.To))
+	fmt.Fprintln(tw, "Rank\tCode\tType\tCount\tMTBF\tMean interval\tLast\tTitle")
+	for i := 0; i < limit(len(Report.Codes), Top); i++ {
+		code := Report.Codes[i]
+		fmt.Fprintf(tw, "%d\t%d\t%s\t%d\t%s\t%s\t%s\t%s\n", i+1, code.Code, structures.ElogTypeName(code.MsgType), code.Count,
+			formatMTBF(code), formatDuration(code.MeanInterval), formatTime(code.Last), code.Title)
+	}
+	fmt.Fprintln(tw, "\nCategory\tCount\tErrors\tWarnings")
+	for _, category := range Report.Categories {
+		fmt.Fprintf(tw, "%s\t%d\t%d\t%d\n", category.Category, category.Count, category.Errors, category.Warnings)
+	}
+	if len(Report.Bursts) > 0 {
+		fmt.Fprintln(tw, "\nBurst code\tCount\tStart\tDuration")
+		for i := 0; i < limit(len(Report.Bursts), Top); i++ {
+			burst := Report.Bursts[i]
+			fmt.Fprintf(tw, "%d\t%d\t%s\t%s\n", burst.Code, burst.Count, formatTime(burst.Start), burst.End.Sub(burst.Start))
+		}
+	}
+	return tw.Flush()
+}
+
+// limit returns the number of entries to list, at most Top of N.
+func limit(N int, Top int) int {
+	if Top > 0 && Top < N {
+		return Top
+	}
+	return N
+}
+
+func formatTime(Time time.Time) string {
+	if Time.IsZero() {
+		return "-"
+	}
+	return Time.Format("2006-01-02 15:04:05")
+}
+
+// formatMTBF formats the MTBF of a code, "-" for codes other than errors and "n/a" when the
+// observed period is too short to compute it, e.g. for a single message.
+func formatMTBF(Code CodeStats) string {
+	switch {
+	case Code.MsgType != structures.ElogError:
+		return "-"
+	case Code.MTBF == 0:
+		return "n/a"
+	default:
+		return formatDuration(Code.MTBF)
+	}
+}
+
+func formatDuration(Duration time.Duration) string {
+	if Duration == 0 {
+		return "-"
+	}
+	return Duration.Round(time.Second).String()
+}
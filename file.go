@@ -7,6 +7,7 @@ import (
 	"net/http"
 	"net/url"
 	"os"
+	"path/filepath"
 	"strconv"
 )
 
@@ -55,6 +56,7 @@ func (c *Client) CreateDirectory(Env string, Dir string) error {
 }
 
 // GetFile will get a file from the controller and save it with the specified filename.
+// A partially downloaded file is removed when the download fails.
 // Example: Source = $TEMP/my_test_file.txt, Filename = my_test_file.txt
 func (c *Client) GetFile(Source string, Filename string) error {
 	c.Client = c.DigestAuthenticate()
@@ -66,6 +68,7 @@ func (c *Client) GetFile(Source string, Filename string) error {
 	if err != nil {
 		return err
 	}
+	defer closeErrorCheck(resp.Body)
 	if resp.StatusCode != http.StatusOK {
 		return fmt.Errorf("HTTP Status Code: %d", resp.StatusCode)
 	}
@@ -74,14 +77,14 @@ func (c *Client) GetFile(Source string, Filename string) error {
 		return err
 	}
 	_, err = io.Copy(file, resp.Body)
-	if err != nil {
-		return err
+	if closeErr := file.Close(); err == nil {
+		err = closeErr
 	}
-	err = file.Close()
 	if err != nil {
+		// don't leave a partial file behind
+		os.Remove(Filename)
 		return err
 	}
-	defer closeErrorCheck(resp.Body)
 	return nil
 }
 
@@ -116,7 +119,7 @@ func (c *Client) UploadFile(SourcePath string, DestPath string) error {
 	}
 	defer closeFileCheck(file)
 	c.Client = c.DigestAuthenticate()
-	req, err := http.NewRequest("PUT", "http://"+c.Host+"/fileservice/"+DestPath+"/"+file.Name(), file)
+	req, err := http.NewRequest("PUT", "http://"+c.Host+"/fileservice/"+DestPath+"/"+filepath.Base(file.Name()), file)
 	if err != nil {
 		return err
 	}
@@ -190,7 +193,7 @@ func (c *Client) GetFileSize(Path string) (string, error) {
 	if err != nil {
 		return "", err
 	}
-	if resp.StatusCode != http.StatusNoContent {
+	if resp.StatusCode != http.StatusNoContent && resp.StatusCode != http.StatusOK {
 		return "", fmt.Errorf("HTTP Status Code: %d", resp.StatusCode)
 	}
 	defer closeErrorCheck(resp.Body)
@@ -5,3 +5,5 @@ go 1.21
 require github.com/icholy/digest v1.1.0
 
 require github.com/gorilla/websocket v1.5.3
+
+require gopkg.in/yaml.v3 v3.0.1
@@ -0,0 +1,93 @@
+package abb
+
+import (
+	"fmt"
+	"time"
+)
+
+// Handshake phases reported by HandshakeTimeoutError.
+const (
+	HandshakeWaitAck     = "wait for ack"
+	HandshakeWaitAckDrop = "wait for ack to drop"
+)
+
+// HandshakeTimeoutError is returned by Handshake.Run when the ack input did not change in time.
+type HandshakeTimeoutError struct {
+	Phase   string
+	Ack     string
+	Timeout time.Duration
+}
+
+func (e *HandshakeTimeoutError) Error() string {
+	return fmt.Sprintf("handshake timed out after %v: %s on %s", e.Timeout, e.Phase, e.Ack)
+}
+
+// Handshake is a request/acknowledge interlock between the robot and a PLC.
+// Request is a digital output set by the robot and Ack is a digital input set by the PLC.
+type Handshake struct {
+	Client  *Client
+	Request string
+	Ack     string
+	// Timeout is applied to each phase of the handshake separately.
+	Timeout time.Duration
+}
+
+// NewHandshake creates a handshake on the given request output and ack input.
+// Example: Request = Local/DRV_1/doReq, Ack = Local/DRV_1/diAck
+func (c *Client) NewHandshake(Request string, Ack string, Timeout time.Duration) *Handshake {
+	return &Handshake{Client: c, Request: Request, Ack: Ack, Timeout: Timeout}
+}
+
+// Run sets the request output and waits for the ack input to go high. It then clears the request
+// and waits for the ack input to drop. A *HandshakeTimeoutError reports the phase that timed out.
+// The request output is always cleared before Run returns.
+func (h *Handshake) Run() error {
+	events, conn, err := h.Client.subscribeToIOSignal(h.Ack)
+	if err != nil {
+		return err
+	}
+	defer func() {
+		conn.Close()
+		// drain any pending update so the subscription goroutine can exit
+		for range events {
+		}
+	}()
+	ack, err := h.Client.GetSignal(h.Ack)
+	if err != nil {
+		return err
+	}
+	if ack.Value == true {
+		return fmt.Errorf("ack %s is already set before the request", h.Ack)
+	}
+	err = h.Client.SetDigitalOutput(h.Request, true)
+	if err != nil {
+		return err
+	}
+	err = h.wait(events, "1", HandshakeWaitAck)
+	if clearErr := h.Client.SetDigitalOutput(h.Request, false); clearErr != nil && err == nil {
+		err = clearErr
+	}
+	if err != nil {
+		return err
+	}
+	return h.wait(events, "0", HandshakeWaitAckDrop)
+}
+
+// wait blocks until the ack input reports the expected value or the phase times out.
+func (h *Handshake) wait(Events chan map[string]string, Value string, Phase string) error {
+	timeout := time.NewTimer(h.Timeout)
+	defer timeout.Stop()
+	for {
+		select {
+		case event, ok := <-Events:
+			if !ok {
+				return fmt.Errorf("subscription on %s closed during %s", h.Ack, Phase)
+			}
+			if event["value"] == Value {
+				return nil
+			}
+		case <-timeout.C:
+			return &HandshakeTimeoutError{Phase: Phase, Ack: h.Ack, Timeout: h.Timeout}
+		}
+	}
+}
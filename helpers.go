@@ -1,9 +1,18 @@
 package abb
 
 import (
+	"bytes"
+	"encoding/json"
 	"fmt"
 	"io"
+	"net/http"
+	"net/url"
 	"os"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gorilla/websocket"
 )
 
 func closeErrorCheck(c io.Closer) {
@@ -17,3 +26,141 @@ func closeFileCheck(f *os.File) {
 		fmt.Println(err)
 	}
 }
+
+// rwsTimeLayout is the timestamp layout used by the controller, e.g. "2024-07-16 T 12:00:00".
+const rwsTimeLayout = "2006-01-02 T 15:04:05"
+
+// parseRWSTime parses a controller timestamp in the local time zone.
+func parseRWSTime(Value string) (time.Time, error) {
+	return time.ParseInLocation(rwsTimeLayout, strings.TrimSpace(Value), time.Local)
+}
+
+// subscribe creates a websocket subscription on the controller for the given resources
+// and returns the open connection. The caller is responsible for closing it.
+func (c *Client) subscribe(Resources ...string) (*websocket.Conn, error) {
+	body := url.Values{}
+	body.Add("resources", strconv.Itoa(len(Resources)))
+	for i, resource := range Resources {
+		index := strconv.Itoa(i + 1)
+		body.Add(index, resource)
+		body.Add(index+"-p", "1")
+	}
+	c.Client = c.DigestAuthenticate()
+	req, err := http.NewRequest("POST", "http://"+c.Host+"/subscription", bytes.NewBufferString(body.Encode()))
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	resp, err := c.Client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer closeErrorCheck(resp.Body)
+	if resp.StatusCode != http.StatusCreated {
+		return nil, fmt.Errorf("HTTP Status Code: %d", resp.StatusCode)
+	}
+	ws_url := resp.Header.Get("Location")
+	var session, session_ab string
+	for _, cookie := range resp.Cookies() {
+		switch cookie.Name {
+		case "-http-session-":
+			session = cookie.Value
+		case "ABBCX":
+			session_ab = cookie.Value
+		default:
+			continue
+		}
+	}
+	requestHeader := http.Header{}
+	cookie1 := &http.Cookie{Name: "-http-session-", Value: session}
+	cookie2 := &http.Cookie{Name: "ABBCX", Value: session_ab}
+	requestHeader.Add("Cookie", cookie1.String()+"; "+cookie2.String())
+	requestHeader.Add("Origin", strings.Split(ws_url, "/poll")[0])
+	requestHeader.Add("Sec-WebSocket-Protocol", "robapi2_subscription")
+	conn, _, err := websocket.DefaultDialer.Dial(ws_url, requestHeader)
+	if err != nil {
+		return nil, err
+	}
+	err = conn.SetReadDeadline(time.Now().Add(60 * time.Second))
+	if err != nil {
+		conn.Close()
+		return nil, err
+	}
+	conn.SetPongHandler(func(string) error {
+		return conn.SetReadDeadline(time.Now().Add(60 * time.Second))
+	})
+	return conn, nil
+}
+
+// getJSONPage is a helper function that decodes a single page of a json=1 listing into Page and
+// returns the URL of the next page, "" if there is none.
+func (c *Client) getJSONPage(URL string, Page interface{}) (string, error) {
+	c.Client = c.DigestAuthenticate()
+	req, err := http.NewRequest("GET", URL, nil)
+	if err != nil {
+		return "", err
+	}
+	resp, err := c.Client.Do(req)
+	if err != nil {
+		return "", err
+	}
+	defer closeErrorCheck(resp.Body)
+	if resp.StatusCode != http.StatusOK {
+		return "", fmt.Errorf("HTTP Status Code: %d", resp.StatusCode)
+	}
+	data, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return "", err
+	}
+	err = json.Unmarshal(data, Page)
+	if err != nil {
+		return "", err
+	}
+	var links struct {
+		Links struct {
+			Base struct {
+				Href string `json:"href"`
+			} `json:"base"`
+			Next struct {
+				Href string `json:"href"`
+			} `json:"next"`
+		} `json:"_links"`
+	}
+	err = json.Unmarshal(data, &links)
+	if err != nil {
+		return "", err
+	}
+	return nextPageURL(URL, links.Links.Base.Href, links.Links.Next.Href), nil
+}
+
+// nextPageURL resolves the href of the next page, "" if there is none. The href is relative to the
+// base link of the response, or to the current page if the response has no base link.
+func nextPageURL(Current string, Base string, Href string) string {
+	if Href == "" {
+		return ""
+	}
+	base, err := url.Parse(Current)
+	if err != nil {
+		return ""
+	}
+	if Base != "" {
+		base, err = base.Parse(Base)
+		if err != nil {
+			return ""
+		}
+	}
+	next, err := base.Parse(Href)
+	if err != nil {
+		return ""
+	}
+	return next.String()
+}
+
+func containsString(List []string, Value string) bool {
+	for _, item := range List {
+		if item == Value {
+			return true
+		}
+	}
+	return false
+}
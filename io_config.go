@@ -0,0 +1,194 @@
+package abb
+
+import (
+	"fmt"
+	"io"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/atmassey/abb-lib-rws/eio"
+	"github.com/atmassey/abb-lib-rws/structures"
+)
+
+// GetSignalInfo returns the full configuration of an IO signal including its cross connections.
+// Example signal: Local/DRV_1/DRV1K1
+func (c *Client) GetSignalInfo(Path string) (*structures.SignalInfo, error) {
+	name := Path[strings.LastIndex(Path, "/")+1:]
+	instance, err := c.GetCfgInstance("EIO", "EIO_SIGNAL", name)
+	if err != nil {
+		return nil, err
+	}
+	attributes := instance.Attributes
+	info := structures.SignalInfo{
+		Path:        Path,
+		Name:        name,
+		Type:        attributes["SignalType"],
+		Device:      attributes["Device"],
+		DeviceMap:   attributes["DeviceMap"],
+		BitLength:   deviceMapLength(attributes["DeviceMap"]),
+		Category:    attributes["Category"],
+		AccessLevel: attributes["Access"],
+		Default:     attributes["Default"],
+		Invert:      cfgBool(attributes["Invert"]),
+	}
+	limits := map[string]*float64{
+		"MinLog":    &info.MinLog,
+		"MaxLog":    &info.MaxLog,
+		"MinPhys":   &info.MinPhys,
+		"MaxPhys":   &info.MaxPhys,
+		"MinBitVal": &info.MinBitVal,
+		"MaxBitVal": &info.MaxBitVal,
+	}
+	for key, field := range limits {
+		value := attributes[key]
+		if value == "" {
+			continue
+		}
+		*field, err = strconv.ParseFloat(value, 64)
+		if err != nil {
+			return nil, fmt.Errorf("invalid %s %q: %w", key, value, err)
+		}
+	}
+	connections, err := c.getCrossConnections()
+	if err != nil {
+		return nil, err
+	}
+	for _, connection := range connections {
+		if crossConnectionUses(connection, name) {
+			info.CrossConnections = append(info.CrossConnections, connection)
+		}
+	}
+	return &info, nil
+}
+
+// CrossConnectionGraph returns all cross connections as an adjacency structure from actor to result signals.
+func (c *Client) CrossConnectionGraph() (*structures.CrossConnectionGraph, error) {
+	connections, err := c.getCrossConnections()
+	if err != nil {
+		return nil, err
+	}
+	graph := structures.CrossConnectionGraph{
+		Connections: connections,
+		Adjacency:   make(map[string][]string),
+	}
+	for _, connection := range connections {
+		for _, actor := range connection.Actors {
+			graph.Adjacency[actor.Signal] = append(graph.Adjacency[actor.Signal], connection.Result)
+		}
+	}
+	return &graph, nil
+}
+
+// WriteCrossConnectionDOT writes a cross connection graph in Graphviz DOT format.
+// Every cross connection is drawn as a box labeled with its logic, inverted actors are drawn dashed.
+func WriteCrossConnectionDOT(W io.Writer, Graph *structures.CrossConnectionGraph) error {
+	var b strings.Builder
+	b.WriteString("digraph cross_connections {\n\trankdir=LR;\n")
+	for _, connection := range Graph.Connections {
+		var logic []string
+		for _, actor := range connection.Actors {
+			term := actor.Signal
+			if actor.Invert {
+				term = "!" + term
+			}
+			logic = append(logic, term)
+			if actor.Operator != "" {
+				logic = append(logic, actor.Operator)
+			}
+		}
+		node := strconv.Quote("cross:" + connection.Name)
+		fmt.Fprintf(&b, "\t%s [shape=box, label=%s];\n", node, strconv.Quote(strings.Join(logic, " ")))
+		for _, actor := range connection.Actors {
+			style := ""
+			if actor.Invert {
+				style = " [style=dashed]"
+			}
+			fmt.Fprintf(&b, "\t%s -> %s%s;\n", strconv.Quote(actor.Signal), node, style)
+		}
+		fmt.Fprintf(&b, "\t%s -> %s;\n", node, strconv.Quote(connection.Result))
+	}
+	b.WriteString("}\n")
+	_, err := io.WriteString(W, b.String())
+	return err
+}
+
+// getCrossConnections is a helper function that returns all EIO_CROSS instances sorted by name.
+func (c *Client) getCrossConnections() ([]structures.CrossConnection, error) {
+	instances, err := c.ListCfgInstances("EIO", "EIO_CROSS")
+	if err != nil {
+		return nil, err
+	}
+	connections := make([]structures.CrossConnection, 0, len(instances))
+	for _, instance := range instances {
+		connections = append(connections, crossConnectionFromAttributes(instance.Attributes))
+	}
+	sort.Slice(connections, func(i, j int) bool {
+		return connections[i].Name < connections[j].Name
+	})
+	return connections, nil
+}
+
+// crossConnectionFromAttributes decodes an EIO_CROSS instance using the decoder of the eio package.
+func crossConnectionFromAttributes(Attributes structures.CfgAttributes) structures.CrossConnection {
+	cross := eio.CrossFromAttributes(Attributes)
+	connection := structures.CrossConnection{Name: cross.Name, Result: cross.Result}
+	for _, actor := range cross.Actors {
+		connection.Actors = append(connection.Actors, structures.CrossConnectionActor{
+			Signal:   actor.Signal,
+			Invert:   actor.Invert,
+			Operator: actor.Operator,
+		})
+	}
+	return connection
+}
+
+func crossConnectionUses(Connection structures.CrossConnection, Signal string) bool {
+	if Connection.Result == Signal {
+		return true
+	}
+	for _, actor := range Connection.Actors {
+		if actor.Signal == Signal {
+			return true
+		}
+	}
+	return false
+}
+
+// cfgBool parses a boolean configuration attribute.
+func cfgBool(Value string) bool {
+	switch strings.ToLower(Value) {
+	case "true", "1", "yes", "on":
+		return true
+	default:
+		return false
+	}
+}
+
+// deviceMapLength returns the number of bits in a device map, e.g. "0-7" -> 8 and "0-3,8-11" -> 8.
+func deviceMapLength(DeviceMap string) int {
+	length := 0
+	for _, part := range strings.Split(DeviceMap, ",") {
+		part = strings.TrimSpace(part)
+		if part == "" {
+			continue
+		}
+		bounds := strings.SplitN(part, "-", 2)
+		first, err := strconv.Atoi(bounds[0])
+		if err != nil {
+			return 0
+		}
+		last := first
+		if len(bounds) == 2 {
+			last, err = strconv.Atoi(bounds[1])
+			if err != nil {
+				return 0
+			}
+		}
+		if last < first {
+			first, last = last, first
+		}
+		length += last - first + 1
+	}
+	return length
+}
@@ -7,6 +7,9 @@ import (
 	"fmt"
 	"net/http"
 	"net/url"
+	"path"
+	"sort"
+	"strconv"
 	"strings"
 	"time"
 
@@ -15,6 +18,7 @@ import (
 )
 
 // GetIOSignals returns a struct of all IO signals on the robot with their names and values.
+// Only the first page of signals is returned, use QuerySignals on large systems.
 func (c *Client) GetIOSignals() (*structures.IOSignals, error) {
 	var signals structures.IOSignals
 	var signalsRaw structures.IOSignalsJson
@@ -78,64 +82,23 @@ func (c *Client) UpdateIODevice(State string, DevicePath string) error {
 // SubscribeToIOSignal is used to subscribe to an IO signal and returns a channel with the signal value and simulation state.
 // Example signal: LOCAL/PANEL/MAN1 for manual mode
 func (c *Client) SubscribeToIOSignal(Signal string) (chan map[string]string, error) {
-	returnChannel := make(chan map[string]string)
-	body := url.Values{}
-	body.Add("resources", "1")
-	body.Add("1", "/rw/iosystem/signals/"+Signal+";state")
-	body.Add("1-p", "1")
-	c.Client = c.DigestAuthenticate()
-	req, err := http.NewRequest("POST", "http://"+c.Host+"/subscription", bytes.NewBufferString(body.Encode()))
-	if err != nil {
-		return nil, err
-	}
-	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
-	resp, err := c.Client.Do(req)
-	if err != nil {
-		return nil, err
-	}
-	defer closeErrorCheck(resp.Body)
-	if resp.StatusCode != http.StatusCreated {
-		return nil, fmt.Errorf("HTTP Status Code: %d", resp.StatusCode)
-	}
-	ws_url := resp.Header.Get("Location")
-	header := resp.Cookies()
-	var session, session_ab string
-	for _, c := range header {
-		switch c.Name {
-		case "-http-session-":
-			session = c.Value
-		case "ABBCX":
-			session_ab = c.Value
-		default:
-			continue
-		}
-	}
-	requestHeader := http.Header{}
-	cookie1 := &http.Cookie{Name: "-http-session-", Value: session}
-	cookie2 := &http.Cookie{Name: "ABBCX", Value: session_ab}
-	requestHeader.Add("Cookie", cookie1.String()+"; "+cookie2.String())
-	requestHeader.Add("Origin", strings.Split(ws_url, "/poll")[0])
-	requestHeader.Add("Sec-WebSocket-Protocol", "robapi2_subscription")
-	conn, _, err := websocket.DefaultDialer.Dial(ws_url, requestHeader)
+	returnChannel, _, err := c.subscribeToIOSignal(Signal)
+	return returnChannel, err
+}
+
+// subscribeToIOSignal subscribes to an IO signal and also returns the websocket connection,
+// closing the connection ends the subscription and closes the channel.
+func (c *Client) subscribeToIOSignal(Signal string) (chan map[string]string, *websocket.Conn, error) {
+	conn, err := c.subscribe("/rw/iosystem/signals/" + Signal + ";state")
 	if err != nil {
-		return nil, err
+		return nil, nil, err
 	}
-	defer conn.Close()
+	returnChannel := make(chan map[string]string)
 	go func() {
 		defer func() {
 			conn.Close()
 			close(returnChannel)
 		}()
-		err = conn.SetReadDeadline(time.Now().Add(60 * time.Second))
-		if err != nil {
-			return
-		}
-		conn.SetPongHandler(func(string) error {
-			if err := conn.SetReadDeadline(time.Now().Add(60 * time.Second)); err != nil {
-				return err
-			}
-			return nil
-		})
 		for {
 			_, message, err := conn.ReadMessage()
 			if err != nil {
@@ -146,13 +109,16 @@ func (c *Client) SubscribeToIOSignal(Signal string) (chan map[string]string, err
 			if err != nil {
 				return
 			}
+			if len(MessageXML.Body.Div.List.Span) < 2 {
+				continue
+			}
 			mapString := make(map[string]string)
 			mapString["value"] = MessageXML.Body.Div.List.Span[0].Text
 			mapString["state"] = MessageXML.Body.Div.List.Span[1].Text
 			returnChannel <- mapString
 		}
 	}()
-	return returnChannel, nil
+	return returnChannel, conn, nil
 }
 
 // UnblockSignals will remove simulation for all simulated logical I/O signals.
@@ -175,3 +141,444 @@ func (c *Client) UnblockSignals() error {
 	defer closeErrorCheck(resp.Body)
 	return nil
 }
+
+// GetSignal returns a single IO signal with its typed value, simulation state and quality.
+// Example signal: Local/DRV_1/DRV1K1
+func (c *Client) GetSignal(Path string) (*structures.Signal, error) {
+	var signalRaw structures.IOSignalJson
+	c.Client = c.DigestAuthenticate()
+	req, err := http.NewRequest("GET", "http://"+c.Host+"/rw/iosystem/signals/"+Path, nil)
+	if err != nil {
+		return nil, err
+	}
+	q := req.URL.Query()
+	q.Add("json", "1")
+	req.URL.RawQuery = q.Encode()
+	resp, err := c.Client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer closeErrorCheck(resp.Body)
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("HTTP Status Code: %d", resp.StatusCode)
+	}
+	err = json.NewDecoder(resp.Body).Decode(&signalRaw)
+	if err != nil {
+		return nil, err
+	}
+	if len(signalRaw.Embedded.State) == 0 {
+		return nil, fmt.Errorf("signal not found: %s", Path)
+	}
+	state := signalRaw.Embedded.State[0]
+	signal := structures.Signal{
+		Path:     Path,
+		Name:     state.Name,
+		Type:     state.Type,
+		Category: state.Category,
+		LValue:   state.LValue,
+		LState:   state.LState,
+		Quality:  state.Quality,
+	}
+	if value, err := typedSignalValue(state.Type, signal.LValue); err == nil {
+		signal.Value = value
+	}
+	return &signal, nil
+}
+
+// typedSignalValue converts a logical signal value to bool for DI/DO, float64 for AI/AO and uint64 for GI/GO.
+func typedSignalValue(Type string, LValue string) (interface{}, error) {
+	switch Type {
+	case "DI", "DO":
+		return LValue == "1", nil
+	case "AI", "AO":
+		return strconv.ParseFloat(LValue, 64)
+	case "GI", "GO":
+		return strconv.ParseUint(LValue, 10, 64)
+	default:
+		return nil, fmt.Errorf("unknown signal type: %s", Type)
+	}
+}
+
+// GetSignalLimits returns the configured logical minimum and maximum of an analog or group signal
+// and the number of bits in its device map.
+func (c *Client) GetSignalLimits(Path string) (*structures.SignalLimits, error) {
+	name := Path[strings.LastIndex(Path, "/")+1:]
+	instance, err := c.GetCfgInstance("EIO", "EIO_SIGNAL", name)
+	if err != nil {
+		return nil, err
+	}
+	attributes := instance.Attributes
+	var limits structures.SignalLimits
+	if value, ok := attributes["MinLog"]; ok && value != "" {
+		limits.MinLog, err = strconv.ParseFloat(value, 64)
+		if err != nil {
+			return nil, fmt.Errorf("invalid MinLog %q: %w", value, err)
+		}
+	}
+	if value, ok := attributes["MaxLog"]; ok && value != "" {
+		limits.MaxLog, err = strconv.ParseFloat(value, 64)
+		if err != nil {
+			return nil, fmt.Errorf("invalid MaxLog %q: %w", value, err)
+		}
+	}
+	limits.BitLength = deviceMapLength(attributes["DeviceMap"])
+	return &limits, nil
+}
+
+// SetDigitalOutput sets the value of a digital output signal.
+func (c *Client) SetDigitalOutput(Path string, Value bool) error {
+	body := url.Values{}
+	if Value {
+		body.Add("lvalue", "1")
+	} else {
+		body.Add("lvalue", "0")
+	}
+	return c.setSignal(Path, "DO", body)
+}
+
+// PulseDigitalOutput sets a digital output signal high for the given duration and then resets it.
+func (c *Client) PulseDigitalOutput(Path string, Duration time.Duration) error {
+	if Duration <= 0 {
+		return fmt.Errorf("invalid pulse duration %v", Duration)
+	}
+	body := url.Values{}
+	body.Add("lvalue", "1")
+	body.Add("mode", "pulse")
+	body.Add("Pulses", "1")
+	body.Add("ActivePulse", strconv.FormatInt(Duration.Milliseconds(), 10))
+	return c.setSignal(Path, "DO", body)
+}
+
+// SetAnalogOutput sets the value of an analog output signal.
+// The value must be within the configured logical limits of the signal, if any are configured.
+func (c *Client) SetAnalogOutput(Path string, Value float64) error {
+	limits, err := c.GetSignalLimits(Path)
+	if err != nil {
+		return err
+	}
+	// limits of 0 and 0 mean the logical limits are not configured
+	definedLimits := limits.MinLog != 0 || limits.MaxLog != 0
+	if definedLimits && (Value < limits.MinLog || Value > limits.MaxLog) {
+		return fmt.Errorf("value %g out of range [%g, %g] for %s", Value, limits.MinLog, limits.MaxLog, Path)
+	}
+	body := url.Values{}
+	body.Add("lvalue", strconv.FormatFloat(Value, 'f', -1, 64))
+	return c.setSignal(Path, "AO", body)
+}
+
+// SetGroupOutput sets the value of a group output signal.
+// The value must fit in the bits of the device map and be within the configured logical limits of the signal.
+func (c *Client) SetGroupOutput(Path string, Value uint64) error {
+	limits, err := c.GetSignalLimits(Path)
+	if err != nil {
+		return err
+	}
+	if limits.BitLength > 0 && limits.BitLength < 64 && Value > 1<<uint(limits.BitLength)-1 {
+		return fmt.Errorf("value %d does not fit in the %d bits of %s", Value, limits.BitLength, Path)
+	}
+	if limits.MaxLog > 0 && (float64(Value) < limits.MinLog || float64(Value) > limits.MaxLog) {
+		return fmt.Errorf("value %d out of range [%g, %g] for %s", Value, limits.MinLog, limits.MaxLog, Path)
+	}
+	body := url.Values{}
+	body.Add("lvalue", strconv.FormatUint(Value, 10))
+	return c.setSignal(Path, "GO", body)
+}
+
+// writeOutput writes a typed value to a digital, analog or group output.
+func (c *Client) writeOutput(Path string, Type string, Value interface{}) error {
+	switch Type {
+	case "DO":
+		return c.SetDigitalOutput(Path, Value.(bool))
+	case "AO":
+		return c.SetAnalogOutput(Path, Value.(float64))
+	case "GO":
+		return c.SetGroupOutput(Path, Value.(uint64))
+	default:
+		return fmt.Errorf("signal %s of type %s is not an output", Path, Type)
+	}
+}
+
+// setSignal is a helper function that checks the signal type and posts a new value.
+func (c *Client) setSignal(Path string, Type string, Body url.Values) error {
+	signal, err := c.GetSignal(Path)
+	if err != nil {
+		return err
+	}
+	if signal.Type != Type {
+		return fmt.Errorf("signal %s is of type %s, expected %s", Path, signal.Type, Type)
+	}
+	return c.postSignal(Path, Body)
+}
+
+// signalPageSize is the number of signals requested per page when listing signals.
+const signalPageSize = 100
+
+// listSignals is a helper function that returns all signals matching the query, following the paging links.
+func (c *Client) listSignals(Query url.Values) ([]structures.Signal, error) {
+	var signals []structures.Signal
+	// copy the query so the paging parameters do not leak into the caller's values
+	q := url.Values{}
+	for key, values := range Query {
+		q[key] = append([]string(nil), values...)
+	}
+	q.Set("json", "1")
+	q.Set("start", "0")
+	q.Set("limit", strconv.Itoa(signalPageSize))
+	next := "http://" + c.Host + "/rw/iosystem/signals?" + q.Encode()
+	for next != "" {
+		var page structures.IOSignalsPageJson
+		var err error
+		next, err = c.getJSONPage(next, &page)
+		if err != nil {
+			return nil, err
+		}
+		for _, state := range page.Embedded.State {
+			signal := structures.Signal{
+				Path:     state.Title,
+				Name:     state.Name,
+				Type:     state.Type,
+				Category: state.Category,
+				LValue:   state.LValue,
+				LState:   state.LState,
+				Quality:  state.Quality,
+			}
+			// a value that cannot be typed is reported untyped rather than failing the whole listing
+			if value, err := typedSignalValue(state.Type, signal.LValue); err == nil {
+				signal.Value = value
+			}
+			signals = append(signals, signal)
+		}
+	}
+	return signals, nil
+}
+
+// SimulateSignal blocks a single signal from the IO system and forces it to the given value.
+// The value must be a bool for digital, a float64 for analog and a uint64 for group signals.
+// Example signal: Local/DRV_1/DRV1K1
+func (c *Client) SimulateSignal(Path string, Value interface{}) error {
+	lvalue, err := formatSignalValue(Value)
+	if err != nil {
+		return err
+	}
+	body := url.Values{}
+	body.Add("lstate", "simulated")
+	body.Add("lvalue", lvalue)
+	return c.postSignal(Path, body)
+}
+
+// UnsimulateSignal removes the simulation from a single signal.
+func (c *Client) UnsimulateSignal(Path string) error {
+	body := url.Values{}
+	body.Add("lstate", "not simulated")
+	return c.postSignal(Path, body)
+}
+
+// ListSimulatedSignals returns all signals that are currently simulated.
+func (c *Client) ListSimulatedSignals() ([]structures.Signal, error) {
+	signals, err := c.listSignals(url.Values{})
+	if err != nil {
+		return nil, err
+	}
+	var simulated []structures.Signal
+	for _, signal := range signals {
+		if signal.LState == "simulated" {
+			simulated = append(simulated, signal)
+		}
+	}
+	return simulated, nil
+}
+
+// formatSignalValue converts a typed signal value to the logical value sent to the controller.
+func formatSignalValue(Value interface{}) (string, error) {
+	switch value := Value.(type) {
+	case bool:
+		if value {
+			return "1", nil
+		}
+		return "0", nil
+	case float64:
+		return strconv.FormatFloat(value, 'f', -1, 64), nil
+	case uint64:
+		return strconv.FormatUint(value, 10), nil
+	case int:
+		return strconv.Itoa(value), nil
+	default:
+		return "", fmt.Errorf("unsupported signal value type %T", Value)
+	}
+}
+
+// postSignal is a helper function that posts new values or states for a signal.
+func (c *Client) postSignal(Path string, Body url.Values) error {
+	c.Client = c.DigestAuthenticate()
+	req, err := http.NewRequest("POST", "http://"+c.Host+"/rw/iosystem/signals/"+Path, bytes.NewBufferString(Body.Encode()))
+	if err != nil {
+		return err
+	}
+	q := req.URL.Query()
+	q.Add("action", "set")
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	req.URL.RawQuery = q.Encode()
+	resp, err := c.Client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer closeErrorCheck(resp.Body)
+	if resp.StatusCode != http.StatusNoContent {
+		return fmt.Errorf("HTTP Status Code: %d", resp.StatusCode)
+	}
+	return nil
+}
+
+// GetIONetworks returns all IO networks on the controller with their logical and physical state.
+func (c *Client) GetIONetworks() ([]structures.IONetwork, error) {
+	var networks []structures.IONetwork
+	next := "http://" + c.Host + "/rw/iosystem/networks?json=1"
+	for next != "" {
+		var page structures.IONetworksJson
+		var err error
+		next, err = c.getJSONPage(next, &page)
+		if err != nil {
+			return nil, err
+		}
+		for _, state := range page.Embedded.State {
+			networks = append(networks, structures.IONetwork{Name: state.Name, LState: state.LState, PState: state.PState})
+		}
+	}
+	return networks, nil
+}
+
+// GetIODevices returns all IO devices on a network with their state, address and identification.
+// Example network: EtherNetIP
+func (c *Client) GetIODevices(Network string) ([]structures.IODevice, error) {
+	var devices []structures.IODevice
+	q := url.Values{}
+	q.Add("network", Network)
+	q.Add("json", "1")
+	next := "http://" + c.Host + "/rw/iosystem/devices?" + q.Encode()
+	for next != "" {
+		var page structures.IODevicesJson
+		var err error
+		next, err = c.getJSONPage(next, &page)
+		if err != nil {
+			return nil, err
+		}
+		for _, state := range page.Embedded.State {
+			devices = append(devices, structures.IODevice{
+				Network:   Network,
+				Name:      state.Name,
+				Path:      state.Title,
+				LState:    state.LState,
+				PState:    state.PState,
+				Address:   state.Address,
+				VendorID:  state.VendorID,
+				ProductID: state.ProductID,
+				Enabled:   state.LState == "enabled",
+			})
+		}
+	}
+	return devices, nil
+}
+
+// DeviceTree returns all networks with their devices and the signals mapped to each device.
+func (c *Client) DeviceTree() (*structures.IODeviceTree, error) {
+	var tree structures.IODeviceTree
+	networks, err := c.GetIONetworks()
+	if err != nil {
+		return nil, err
+	}
+	signals, err := c.listSignals(url.Values{})
+	if err != nil {
+		return nil, err
+	}
+	// signal paths are <network>/<device>/<signal>
+	byDevice := make(map[string][]structures.Signal)
+	for _, signal := range signals {
+		i := strings.LastIndex(signal.Path, "/")
+		if i < 0 {
+			tree.Unmapped = append(tree.Unmapped, signal)
+			continue
+		}
+		byDevice[signal.Path[:i]] = append(byDevice[signal.Path[:i]], signal)
+	}
+	for _, network := range networks {
+		node := structures.IONetworkNode{Network: network}
+		devices, err := c.GetIODevices(network.Name)
+		if err != nil {
+			return nil, err
+		}
+		for _, device := range devices {
+			node.Devices = append(node.Devices, structures.IODeviceNode{Device: device, Signals: byDevice[device.Path]})
+			delete(byDevice, device.Path)
+		}
+		tree.Networks = append(tree.Networks, node)
+	}
+	for _, remaining := range byDevice {
+		tree.Unmapped = append(tree.Unmapped, remaining...)
+	}
+	sort.Slice(tree.Unmapped, func(i, j int) bool {
+		return tree.Unmapped[i].Path < tree.Unmapped[j].Path
+	})
+	return &tree, nil
+}
+
+// QuerySignals returns the signals matching the filter. Network, device, type and category are
+// filtered by the controller and checked again locally, the name pattern is matched locally using path.Match syntax.
+// All pages of the result are fetched.
+func (c *Client) QuerySignals(Filter structures.SignalFilter) (*structures.SignalSet, error) {
+	if Filter.NamePattern != "" {
+		if _, err := path.Match(Filter.NamePattern, ""); err != nil {
+			return nil, fmt.Errorf("invalid name pattern %q: %w", Filter.NamePattern, err)
+		}
+	}
+	query := url.Values{}
+	if Filter.Network != "" {
+		query.Add("network", Filter.Network)
+	}
+	if Filter.Device != "" {
+		query.Add("device", Filter.Device)
+	}
+	if Filter.Type != "" {
+		query.Add("type", Filter.Type)
+	}
+	if Filter.Category != "" {
+		query.Add("category", Filter.Category)
+	}
+	signals, err := c.listSignals(query)
+	if err != nil {
+		return nil, err
+	}
+	set := structures.SignalSet{ByName: make(map[string]*structures.Signal)}
+	for _, signal := range signals {
+		if !signalMatches(signal, Filter) {
+			continue
+		}
+		if Filter.NamePattern != "" {
+			if matched, _ := path.Match(Filter.NamePattern, signal.Name); !matched {
+				continue
+			}
+		}
+		set.Signals = append(set.Signals, signal)
+	}
+	for i := range set.Signals {
+		set.ByName[set.Signals[i].Name] = &set.Signals[i]
+	}
+	return &set, nil
+}
+
+// signalMatches checks the network, device, type and category of a signal against a filter.
+func signalMatches(Signal structures.Signal, Filter structures.SignalFilter) bool {
+	parts := strings.Split(Signal.Path, "/")
+	if Filter.Network != "" && (len(parts) < 3 || parts[0] != Filter.Network) {
+		return false
+	}
+	if Filter.Device != "" && (len(parts) < 3 || parts[1] != Filter.Device) {
+		return false
+	}
+	if Filter.Type != "" && Signal.Type != Filter.Type {
+		return false
+	}
+	if Filter.Category != "" && Signal.Category != Filter.Category {
+		return false
+	}
+	return true
+}
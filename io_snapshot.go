@@ -0,0 +1,128 @@
+package abb
+
+import (
+	"fmt"
+	"net/url"
+	"sort"
+	"time"
+
+	"github.com/atmassey/abb-lib-rws/structures"
+)
+
+// TakeIOSnapshot captures the value and simulation state of every signal and the state of every IO device.
+// The snapshot can be serialized to JSON and compared later with DiffIOSnapshots.
+func (c *Client) TakeIOSnapshot() (*structures.IOSnapshot, error) {
+	snapshot := structures.IOSnapshot{Time: time.Now()}
+	signals, err := c.listSignals(url.Values{})
+	if err != nil {
+		return nil, err
+	}
+	for _, signal := range signals {
+		snapshot.Signals = append(snapshot.Signals, structures.SignalSnapshot{
+			Path:      signal.Path,
+			Type:      signal.Type,
+			LValue:    signal.LValue,
+			Simulated: signal.LState == "simulated",
+		})
+	}
+	networks, err := c.GetIONetworks()
+	if err != nil {
+		return nil, err
+	}
+	for _, network := range networks {
+		devices, err := c.GetIODevices(network.Name)
+		if err != nil {
+			return nil, err
+		}
+		for _, device := range devices {
+			snapshot.Devices = append(snapshot.Devices, structures.DeviceSnapshot{
+				Path:   device.Path,
+				LState: device.LState,
+				PState: device.PState,
+			})
+		}
+	}
+	return &snapshot, nil
+}
+
+// DiffIOSnapshots lists the signals and devices that changed between snapshot A and B, sorted by path.
+func DiffIOSnapshots(A *structures.IOSnapshot, B *structures.IOSnapshot) *structures.IOSnapshotDiff {
+	var diff structures.IOSnapshotDiff
+	before := make(map[string]structures.SignalSnapshot)
+	for _, signal := range A.Signals {
+		before[signal.Path] = signal
+	}
+	for _, after := range B.Signals {
+		previous, ok := before[after.Path]
+		delete(before, after.Path)
+		if ok && previous.LValue == after.LValue && previous.Simulated == after.Simulated {
+			continue
+		}
+		diff.Signals = append(diff.Signals, structures.SignalChange{
+			Path:            after.Path,
+			Type:            after.Type,
+			Before:          previous.LValue,
+			After:           after.LValue,
+			SimulatedBefore: previous.Simulated,
+			SimulatedAfter:  after.Simulated,
+		})
+	}
+	for _, removed := range before {
+		diff.Signals = append(diff.Signals, structures.SignalChange{
+			Path:            removed.Path,
+			Type:            removed.Type,
+			Before:          removed.LValue,
+			SimulatedBefore: removed.Simulated,
+		})
+	}
+	devices := make(map[string]structures.DeviceSnapshot)
+	for _, device := range A.Devices {
+		devices[device.Path] = device
+	}
+	for _, after := range B.Devices {
+		previous, ok := devices[after.Path]
+		delete(devices, after.Path)
+		if ok && previous == after {
+			continue
+		}
+		diff.Devices = append(diff.Devices, structures.DeviceChange{Path: after.Path, Before: previous, After: after})
+	}
+	for _, removed := range devices {
+		diff.Devices = append(diff.Devices, structures.DeviceChange{Path: removed.Path, Before: removed})
+	}
+	sort.Slice(diff.Signals, func(i, j int) bool { return diff.Signals[i].Path < diff.Signals[j].Path })
+	sort.Slice(diff.Devices, func(i, j int) bool { return diff.Devices[i].Path < diff.Devices[j].Path })
+	return &diff
+}
+
+// RestoreOutputs writes the digital, analog and group output values of a snapshot back to the controller.
+// Only outputs whose current value differs from the snapshot are written. Simulated outputs are skipped.
+// With DryRun set nothing is written and the returned changes show what would be written.
+func (c *Client) RestoreOutputs(Snapshot *structures.IOSnapshot, DryRun bool) ([]structures.SignalChange, error) {
+	var changes []structures.SignalChange
+	for _, saved := range Snapshot.Signals {
+		if saved.Type != "DO" && saved.Type != "AO" && saved.Type != "GO" {
+			continue
+		}
+		current, err := c.GetSignal(saved.Path)
+		if err != nil {
+			return changes, err
+		}
+		if current.LState == "simulated" || current.LValue == saved.LValue {
+			continue
+		}
+		change := structures.SignalChange{Path: saved.Path, Type: saved.Type, Before: current.LValue, After: saved.LValue}
+		if !DryRun {
+			value, err := typedSignalValue(saved.Type, saved.LValue)
+			if err != nil {
+				return changes, err
+			}
+			err = c.writeOutput(saved.Path, saved.Type, value)
+			if err != nil {
+				return changes, fmt.Errorf("restore %s: %w", saved.Path, err)
+			}
+		}
+		changes = append(changes, change)
+	}
+	return changes, nil
+}
@@ -0,0 +1,280 @@
+package abb
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/atmassey/abb-lib-rws/structures"
+	"gopkg.in/yaml.v3"
+)
+
+// ioTestPollInterval is the interval the expected signals are polled with during an IO test step.
+const ioTestPollInterval = 20 * time.Millisecond
+
+// LoadIOTestPlan reads an IO test plan from a file. Files with a .yaml or .yml extension are decoded
+// as YAML, all other files as JSON.
+func LoadIOTestPlan(Path string) (*structures.IOTestPlan, error) {
+	f, err := os.Open(Path)
+	if err != nil {
+		return nil, err
+	}
+	defer closeErrorCheck(f)
+	var plan *structures.IOTestPlan
+	switch strings.ToLower(filepath.Ext(Path)) {
+	case ".yaml", ".yml":
+		plan, err = ParseIOTestPlanYAML(f)
+	default:
+		plan, err = ParseIOTestPlan(f)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", Path, err)
+	}
+	return plan, nil
+}
+
+// ParseIOTestPlan decodes an IO test plan from JSON.
+func ParseIOTestPlan(R io.Reader) (*structures.IOTestPlan, error) {
+	var plan structures.IOTestPlan
+	decoder := json.NewDecoder(R)
+	decoder.DisallowUnknownFields()
+	err := decoder.Decode(&plan)
+	if err != nil {
+		return nil, err
+	}
+	err = validateIOTestPlan(plan)
+	if err != nil {
+		return nil, err
+	}
+	return &plan, nil
+}
+
+// ParseIOTestPlanYAML decodes an IO test plan from YAML, using the same keys as the JSON format.
+func ParseIOTestPlanYAML(R io.Reader) (*structures.IOTestPlan, error) {
+	var plan structures.IOTestPlan
+	decoder := yaml.NewDecoder(R)
+	decoder.KnownFields(true)
+	err := decoder.Decode(&plan)
+	if err != nil {
+		return nil, err
+	}
+	err = validateIOTestPlan(plan)
+	if err != nil {
+		return nil, err
+	}
+	return &plan, nil
+}
+
+// validateIOTestPlan checks every step of a plan has a signal and a value.
+func validateIOTestPlan(Plan structures.IOTestPlan) error {
+	for i, step := range Plan.Steps {
+		if step.Signal == "" || step.Value == "" {
+			return fmt.Errorf("step %d (%s): signal and value are required", i+1, step.Name)
+		}
+	}
+	return nil
+}
+
+// RunIOTestPlan drives every step of the plan and verifies the expected signals, measuring the response time.
+// Afterwards every simulated signal is set back to the simulation state it had before the test and every
+// written output is set back to its original value. Signals that can't be restored are reported in the
+// cleanup error of the report. If a simulated signal can't be restored, UnblockSignals removes the
+// simulation of all signals, including signals that were simulated before the test.
+func (c *Client) RunIOTestPlan(Plan structures.IOTestPlan) (*structures.IOTestReport, error) {
+	if len(Plan.Steps) == 0 {
+		return nil, fmt.Errorf("test plan %s has no steps", Plan.Name)
+	}
+	report := structures.IOTestReport{Plan: Plan.Name, StartTime: time.Now(), Passed: true}
+	simulated := make(map[string]structures.Signal)
+	written := make(map[string]string)
+	var order []string
+	for _, step := range Plan.Steps {
+		result := c.runIOTestStep(step, simulated, written, &order)
+		if !result.Passed {
+			report.Passed = false
+		}
+		report.Steps = append(report.Steps, result)
+	}
+	if err := c.restoreIOTest(simulated, written, order); err != nil {
+		report.CleanupError = err.Error()
+		report.Passed = false
+	}
+	report.EndTime = time.Now()
+	return &report, nil
+}
+
+// runIOTestStep drives a single step and records every touched signal for the cleanup.
+// Simulated holds the state of each simulated signal before the test, Written the original value of each written output.
+func (c *Client) runIOTestStep(Step structures.IOTestStep, Simulated map[string]structures.Signal, Written map[string]string, Order *[]string) structures.IOTestStepResult {
+	result := structures.IOTestStepResult{Name: Step.Name, Signal: Step.Signal, Value: Step.Value}
+	signal, err := c.GetSignal(Step.Signal)
+	if err != nil {
+		result.Error = err.Error()
+		return result
+	}
+	value, err := typedSignalValue(signal.Type, Step.Value)
+	if err != nil {
+		result.Error = err.Error()
+		return result
+	}
+	_, wasSimulated := Simulated[Step.Signal]
+	_, wasWritten := Written[Step.Signal]
+	start := time.Now()
+	if Step.Simulate {
+		err = c.SimulateSignal(Step.Signal, value)
+		if err == nil && !wasSimulated {
+			Simulated[Step.Signal] = *signal
+		}
+	} else {
+		err = c.writeOutput(Step.Signal, signal.Type, value)
+		if err == nil && !wasWritten {
+			Written[Step.Signal] = signal.LValue
+		}
+	}
+	if err != nil {
+		result.Error = err.Error()
+		return result
+	}
+	if !wasSimulated && !wasWritten {
+		*Order = append(*Order, Step.Signal)
+	}
+	timeout := time.Duration(Step.TimeoutMs) * time.Millisecond
+	if timeout <= 0 {
+		timeout = time.Second
+	}
+	for {
+		result.Expectations = result.Expectations[:0]
+		result.Passed = true
+		for _, expect := range Step.Expect {
+			actual, err := c.GetSignal(expect.Signal)
+			check := structures.IOTestExpectationResult{Signal: expect.Signal, Expected: expect.Value}
+			if err != nil {
+				check.Actual = err.Error()
+			} else {
+				check.Actual = actual.LValue
+				check.Passed = signalValuesEqual(actual.Type, actual.LValue, expect.Value)
+			}
+			if !check.Passed {
+				result.Passed = false
+			}
+			result.Expectations = append(result.Expectations, check)
+		}
+		result.ResponseTime = time.Since(start)
+		if result.Passed || result.ResponseTime > timeout {
+			break
+		}
+		time.Sleep(ioTestPollInterval)
+	}
+	if !result.Passed {
+		result.Error = fmt.Sprintf("expectations not met within %v", timeout)
+	}
+	return result
+}
+
+// restoreIOTest restores all outputs written and signals simulated by an IO test, newest first.
+// A signal that was already simulated before the test is simulated again with its original value.
+// When a simulated signal can't be restored, simulation is removed from all signals with UnblockSignals
+// as a last resort so no signal stays simulated on the cell.
+func (c *Client) restoreIOTest(Simulated map[string]structures.Signal, Written map[string]string, Order []string) error {
+	var failed []string
+	unblock := false
+	for i := len(Order) - 1; i >= 0; i-- {
+		path := Order[i]
+		if original, ok := Simulated[path]; ok {
+			var err error
+			if original.LState == "simulated" {
+				var value interface{}
+				value, err = typedSignalValue(original.Type, original.LValue)
+				if err == nil {
+					err = c.SimulateSignal(path, value)
+				}
+			} else {
+				err = c.UnsimulateSignal(path)
+			}
+			if err != nil {
+				failed = append(failed, fmt.Sprintf("%s: %v", path, err))
+				unblock = true
+			}
+		}
+		if original, ok := Written[path]; ok {
+			signal, err := c.GetSignal(path)
+			if err == nil {
+				var value interface{}
+				value, err = typedSignalValue(signal.Type, original)
+				if err == nil {
+					err = c.writeOutput(path, signal.Type, value)
+				}
+			}
+			if err != nil {
+				failed = append(failed, fmt.Sprintf("%s: %v", path, err))
+			}
+		}
+	}
+	if len(failed) == 0 {
+		return nil
+	}
+	err := fmt.Errorf("unable to restore %s", strings.Join(failed, "; "))
+	if !unblock {
+		return err
+	}
+	unblockErr := c.UnblockSignals()
+	if unblockErr != nil {
+		return fmt.Errorf("%v; unable to unblock signals: %v", err, unblockErr)
+	}
+	return fmt.Errorf("%v; simulation removed from all signals", err)
+}
+
+// signalValuesEqual compares two logical values of a signal numerically.
+func signalValuesEqual(Type string, A string, B string) bool {
+	a, errA := typedSignalValue(Type, A)
+	b, errB := typedSignalValue(Type, B)
+	if errA != nil || errB != nil {
+		return A == B
+	}
+	return a == b
+}
+
+// WriteIOTestReportJSON writes an IO test report as indented JSON.
+func WriteIOTestReportJSON(W io.Writer, Report *structures.IOTestReport) error {
+	encoder := json.NewEncoder(W)
+	encoder.SetIndent("", "  ")
+	return encoder.Encode(Report)
+}
+
+// WriteIOTestReportMarkdown writes an IO test report as a Markdown table.
+func WriteIOTestReportMarkdown(W io.Writer, Report *structures.IOTestReport) error {
+	var b strings.Builder
+	status := "PASSED"
+	if !Report.Passed {
+		status = "FAILED"
+	}
+	fmt.Fprintf(&b, "# IO checkout: %s\n\n", Report.Plan)
+	fmt.Fprintf(&b, "**Result:** %s  \n**Started:** %s  \n**Duration:** %v\n\n", status, Report.StartTime.Format(time.RFC3339), Report.EndTime.Sub(Report.StartTime).Round(time.Millisecond))
+	b.WriteString("| Step | Signal | Value | Result | Response | Details |\n")
+	b.WriteString("|---|---|---|---|---|---|\n")
+	for _, step := range Report.Steps {
+		result := "pass"
+		if !step.Passed {
+			result = "**fail**"
+		}
+		var details []string
+		for _, expect := range step.Expectations {
+			if !expect.Passed {
+				details = append(details, fmt.Sprintf("%s expected %s got %s", expect.Signal, expect.Expected, expect.Actual))
+			}
+		}
+		if step.Error != "" {
+			details = append(details, step.Error)
+		}
+		fmt.Fprintf(&b, "| %s | %s | %s | %s | %v | %s |\n", step.Name, step.Signal, step.Value, result, step.ResponseTime.Round(time.Millisecond), strings.Join(details, "; "))
+	}
+	if Report.CleanupError != "" {
+		fmt.Fprintf(&b, "\n**Cleanup:** %s\n", Report.CleanupError)
+	}
+	_, err := io.WriteString(W, b.String())
+	return err
+}
@@ -0,0 +1,226 @@
+package abb
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strconv"
+
+	"github.com/atmassey/abb-lib-rws/structures"
+)
+
+// PrepareJog checks that the controller can be jogged remotely and takes motion mastership.
+// The controller must be in manual mode and RMMP modify or exec privilege must already be granted
+// through RequestRMMP. Operation mode and mechanical unit are checked once here and remembered
+// on the client until StopJog, which stops the motion and releases mastership.
+func (c *Client) PrepareJog(Mechunit string) error {
+	err := c.checkJogPreconditions(Mechunit)
+	if err != nil {
+		return err
+	}
+	err = c.RequestMastershipIndividual("motion")
+	if err != nil {
+		return err
+	}
+	c.jog = jogSession{Mechunit: Mechunit}
+	return nil
+}
+
+// Jog moves a mechanical unit by one jog step.
+// CoordSystem can be "Joint" for axis jogging or "Base", "World", "Tool" or "Wobj" for cartesian jogging.
+// The controller scales the jog speed by the speed ratio set with SetSpeedRatio.
+// The mechanical unit must be prepared with PrepareJog. Only the RMMP privilege is checked
+// before every jog step, the jog mode is only set again when the coordinate system changes.
+func (c *Client) Jog(Mechunit string, Increments structures.JogIncrements, CoordSystem string) error {
+	mode, err := jogMode(CoordSystem)
+	if err != nil {
+		return err
+	}
+	values, err := jogValues(Increments)
+	if err != nil {
+		return err
+	}
+	if c.jog.Mechunit == "" || c.jog.Mechunit != Mechunit {
+		return fmt.Errorf("mechunit %s not prepared for jogging, call PrepareJog first", Mechunit)
+	}
+	err = c.checkJogPrivilege()
+	if err != nil {
+		return err
+	}
+	if c.jog.CoordSystem != CoordSystem {
+		err = c.setJogMode(Mechunit, mode, CoordSystem)
+		if err != nil {
+			return err
+		}
+		c.jog.CoordSystem = CoordSystem
+	}
+	return c.sendJog(values)
+}
+
+// StopJog stops any ongoing jog motion and releases motion mastership.
+func (c *Client) StopJog() error {
+	c.jog = jogSession{}
+	err := c.sendJog(make([]int, 6))
+	if err != nil {
+		return err
+	}
+	return c.ReleaseMastershipIndividual("motion")
+}
+
+// jogSession is the mechanical unit prepared by PrepareJog and the last coordinate system jogged in.
+type jogSession struct {
+	Mechunit    string
+	CoordSystem string
+}
+
+// checkJogPreconditions checks the controller is in manual mode, RMMP modify or exec privilege
+// is granted and the mechanical unit exists.
+func (c *Client) checkJogPreconditions(Mechunit string) error {
+	mode, err := c.GetOperationMode()
+	if err != nil {
+		return err
+	}
+	if mode != "MANR" && mode != "MANF" {
+		return fmt.Errorf("controller must be in manual mode to jog, current mode: %s", mode)
+	}
+	err = c.checkJogPrivilege()
+	if err != nil {
+		return err
+	}
+	mechUnits, err := c.GetMechUnits()
+	if err != nil {
+		return err
+	}
+	for _, title := range mechUnits.Title {
+		if title == Mechunit {
+			return nil
+		}
+	}
+	return fmt.Errorf("mechunit not found: %s", Mechunit)
+}
+
+// checkJogPrivilege checks RMMP modify or exec privilege is granted.
+func (c *Client) checkJogPrivilege() error {
+	privilege, err := c.GetRMMPState()
+	if err != nil {
+		return err
+	}
+	if privilege != "modify" && privilege != "exec" {
+		return fmt.Errorf("RMMP privilege required to jog, current privilege: %s", privilege)
+	}
+	return nil
+}
+
+// jogMode returns the jog mode of a coordinate system.
+func jogMode(CoordSystem string) (string, error) {
+	switch CoordSystem {
+	case "Joint":
+		return "AxisGroup1", nil
+	case "Base", "World", "Tool", "Wobj":
+		return "Cartesian", nil
+	default:
+		return "", fmt.Errorf("invalid coordinate system: %s", CoordSystem)
+	}
+}
+
+// jogValues returns the jog values of all six axes after checking they are within -100 and 100.
+func jogValues(Increments structures.JogIncrements) ([]int, error) {
+	values := []int{Increments.Axis1, Increments.Axis2, Increments.Axis3, Increments.Axis4, Increments.Axis5, Increments.Axis6}
+	for i, value := range values {
+		if value < -100 || value > 100 {
+			return nil, fmt.Errorf("invalid jog value %d for axis %d", value, i+1)
+		}
+	}
+	return values, nil
+}
+
+// setJogMode sets the jog mode and coordinate system of a mechanical unit.
+func (c *Client) setJogMode(Mechunit string, Mode string, CoordSystem string) error {
+	body := url.Values{}
+	body.Add("jog-mode", Mode)
+	if CoordSystem != "Joint" {
+		body.Add("coord-system", CoordSystem)
+	}
+	c.Client = c.DigestAuthenticate()
+	req, err := http.NewRequest("POST", "http://"+c.Host+"/rw/motionsystem/mechunits/"+Mechunit, bytes.NewBufferString(body.Encode()))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	q := req.URL.Query()
+	q.Add("action", "set")
+	req.URL.RawQuery = q.Encode()
+	resp, err := c.Client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer closeErrorCheck(resp.Body)
+	if resp.StatusCode != http.StatusNoContent {
+		return fmt.Errorf("HTTP Status: %v", resp.StatusCode)
+	}
+	return nil
+}
+
+// getChangeCount returns the motion system change count required by every jog request.
+func (c *Client) getChangeCount() (string, error) {
+	var changeCount structures.ChangeCountJson
+	c.Client = c.DigestAuthenticate()
+	req, err := http.NewRequest("GET", "http://"+c.Host+"/rw/motionsystem", nil)
+	if err != nil {
+		return "", err
+	}
+	q := req.URL.Query()
+	q.Add("resource", "change-count")
+	q.Add("json", "1")
+	req.URL.RawQuery = q.Encode()
+	resp, err := c.Client.Do(req)
+	if err != nil {
+		return "", err
+	}
+	defer closeErrorCheck(resp.Body)
+	if resp.StatusCode != http.StatusOK {
+		return "", fmt.Errorf("HTTP Status: %v", resp.StatusCode)
+	}
+	err = json.NewDecoder(resp.Body).Decode(&changeCount)
+	if err != nil {
+		return "", err
+	}
+	if len(changeCount.Embedded.State) == 0 {
+		return "", fmt.Errorf("change count not found")
+	}
+	return changeCount.Embedded.State[0].ChangeCount, nil
+}
+
+// sendJog posts the jog values for all six axes.
+func (c *Client) sendJog(Values []int) error {
+	changeCount, err := c.getChangeCount()
+	if err != nil {
+		return err
+	}
+	body := url.Values{}
+	for i, value := range Values {
+		body.Add("axis"+strconv.Itoa(i+1), strconv.Itoa(value))
+	}
+	body.Add("ccount", changeCount)
+	body.Add("inc-mode", "Small")
+	c.Client = c.DigestAuthenticate()
+	req, err := http.NewRequest("POST", "http://"+c.Host+"/rw/motionsystem", bytes.NewBufferString(body.Encode()))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	q := req.URL.Query()
+	q.Add("action", "jog")
+	req.URL.RawQuery = q.Encode()
+	resp, err := c.Client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer closeErrorCheck(resp.Body)
+	if resp.StatusCode != http.StatusNoContent {
+		return fmt.Errorf("HTTP Status: %v", resp.StatusCode)
+	}
+	return nil
+}
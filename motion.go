@@ -6,11 +6,18 @@ import (
 	"fmt"
 	"net/http"
 	"net/url"
+	"strconv"
 	"strings"
 
 	"github.com/atmassey/abb-lib-rws/structures"
 )
 
+// Supervision levels are given in percent of the default tuning.
+const (
+	MinSupervisionLevel = 1
+	MaxSupervisionLevel = 300
+)
+
 // GetMechUnits returns a list of all the mechunits on the robot controller
 func (c *Client) GetMechUnits() (*structures.MechUnits, error) {
 	mechUnits := structures.MechUnitsJson{}
@@ -112,6 +119,7 @@ func (c *Client) SetMotionSupervisionMode(MechanicalUnit string, Mode bool) erro
 	if err != nil {
 		return err
 	}
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
 	q := req.URL.Query()
 	q.Add("action", "set-mode")
 	req.URL.RawQuery = q.Encode()
@@ -126,16 +134,21 @@ func (c *Client) SetMotionSupervisionMode(MechanicalUnit string, Mode bool) erro
 	return nil
 }
 
-// SetMotionSupervisionSensitivity sets the motion supervision sensitivity for a specific mechanical unit
-func (c *Client) SetMotionSupervisionSensitivity(MechanicalUnit string, Sensitivity string) error {
+// SetMotionSupervisionSensitivity sets the motion supervision sensitivity for a specific mechanical unit.
+// The sensitivity is given in percent and must be between MinSupervisionLevel and MaxSupervisionLevel.
+func (c *Client) SetMotionSupervisionSensitivity(MechanicalUnit string, Sensitivity int) error {
+	if Sensitivity < MinSupervisionLevel || Sensitivity > MaxSupervisionLevel {
+		return fmt.Errorf("invalid sensitivity %d", Sensitivity)
+	}
 	body := url.Values{}
-	body.Add("sensitivity", Sensitivity)
+	body.Add("sensitivity", strconv.Itoa(Sensitivity))
 	body.Add("mechunit-name", MechanicalUnit)
 	c.Client = c.DigestAuthenticate()
 	req, err := http.NewRequest("POST", "http://"+c.Host+"/rw/motionsystem/motionsupervision", bytes.NewBufferString(body.Encode()))
 	if err != nil {
 		return err
 	}
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
 	q := req.URL.Query()
 	q.Add("action", "set-level")
 	req.URL.RawQuery = q.Encode()
@@ -166,6 +179,7 @@ func (c *Client) SetPathSupervisionMode(Mode bool, MechUnit string) error {
 	if err != nil {
 		return err
 	}
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
 	q := req.URL.Query()
 	q.Add("action", "set-mode")
 	req.URL.RawQuery = q.Encode()
@@ -180,16 +194,21 @@ func (c *Client) SetPathSupervisionMode(Mode bool, MechUnit string) error {
 	return nil
 }
 
-// SetPathSupervisionLevel sets the path supervision level for a specific mechanical unit
-func (c *Client) SetPathSupervisionLevel(Level string, MechUnit string) error {
+// SetPathSupervisionLevel sets the path supervision level for a specific mechanical unit.
+// The level is given in percent and must be between MinSupervisionLevel and MaxSupervisionLevel.
+func (c *Client) SetPathSupervisionLevel(Level int, MechUnit string) error {
+	if Level < MinSupervisionLevel || Level > MaxSupervisionLevel {
+		return fmt.Errorf("invalid level %d", Level)
+	}
 	body := url.Values{}
-	body.Add("level", Level)
+	body.Add("level", strconv.Itoa(Level))
 	body.Add("mechunit", MechUnit)
 	c.Client = c.DigestAuthenticate()
 	req, err := http.NewRequest("POST", "http://"+c.Host+"/rw/motionsystem/pathsupervision", bytes.NewBufferString(body.Encode()))
 	if err != nil {
 		return err
 	}
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
 	q := req.URL.Query()
 	q.Add("action", "set-level")
 	req.URL.RawQuery = q.Encode()
@@ -394,3 +413,151 @@ func (c *Client) SetModifyAllPostions(CheckLimit bool, CheckDeactAxes bool) erro
 	defer closeErrorCheck(resp.Body)
 	return nil
 }
+
+// GetAxisCalibration returns the calibration data for a specific mechanical unit and axis
+func (c *Client) GetAxisCalibration(Mechunit string, Axis int) (*structures.AxisCalibration, error) {
+	var calibration structures.AxisCalibrationJson
+	c.Client = c.DigestAuthenticate()
+	req, err := http.NewRequest("GET", "http://"+c.Host+"/rw/motionsystem/mechunits/"+Mechunit+"/axes/"+strconv.Itoa(Axis), nil)
+	if err != nil {
+		return nil, err
+	}
+	q := req.URL.Query()
+	q.Add("resource", "calib")
+	q.Add("json", "1")
+	req.URL.RawQuery = q.Encode()
+	resp, err := c.Client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("HTTP Status: %v", resp.StatusCode)
+	}
+	defer closeErrorCheck(resp.Body)
+	err = json.NewDecoder(resp.Body).Decode(&calibration)
+	if err != nil {
+		return nil, err
+	}
+	if len(calibration.Embedded.State) == 0 {
+		return nil, fmt.Errorf("calibration data not found for %s axis %d", Mechunit, Axis)
+	}
+	state := calibration.Embedded.State[0]
+	return &structures.AxisCalibration{
+		Axis:              Axis,
+		Status:            state.CalibStatus,
+		CalibOffset:       state.CalibOffset,
+		CommutationOffset: state.CommOffset,
+	}, nil
+}
+
+// GetMotionSupervision returns the motion supervision mode and sensitivity for a specific mechanical unit
+func (c *Client) GetMotionSupervision(MechUnit string) (*structures.MotionSupervision, error) {
+	state, err := c.getSupervision("motionsupervision", "mechunit-name", MechUnit)
+	if err != nil {
+		return nil, err
+	}
+	sensitivity, err := strconv.Atoi(state.Level)
+	if err != nil {
+		return nil, fmt.Errorf("invalid sensitivity %q: %w", state.Level, err)
+	}
+	return &structures.MotionSupervision{
+		MechUnit:    MechUnit,
+		Enabled:     strings.EqualFold(state.Mode, "true") || strings.EqualFold(state.Mode, "on"),
+		Sensitivity: sensitivity,
+	}, nil
+}
+
+// GetPathSupervision returns the path supervision mode and level for a specific mechanical unit
+func (c *Client) GetPathSupervision(MechUnit string) (*structures.PathSupervision, error) {
+	state, err := c.getSupervision("pathsupervision", "mechunit", MechUnit)
+	if err != nil {
+		return nil, err
+	}
+	level, err := strconv.Atoi(state.Level)
+	if err != nil {
+		return nil, fmt.Errorf("invalid level %q: %w", state.Level, err)
+	}
+	return &structures.PathSupervision{
+		MechUnit: MechUnit,
+		Enabled:  strings.EqualFold(state.Mode, "true") || strings.EqualFold(state.Mode, "on"),
+		Level:    level,
+	}, nil
+}
+
+// getSupervision is a helper function that reads the state of a motion system supervision resource.
+func (c *Client) getSupervision(Resource string, MechUnitKey string, MechUnit string) (*structures.SupervisionJsonMeta, error) {
+	var supervision structures.SupervisionJson
+	c.Client = c.DigestAuthenticate()
+	req, err := http.NewRequest("GET", "http://"+c.Host+"/rw/motionsystem/"+Resource, nil)
+	if err != nil {
+		return nil, err
+	}
+	q := req.URL.Query()
+	q.Add(MechUnitKey, MechUnit)
+	q.Add("json", "1")
+	req.URL.RawQuery = q.Encode()
+	resp, err := c.Client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("HTTP Status: %v", resp.StatusCode)
+	}
+	defer closeErrorCheck(resp.Body)
+	err = json.NewDecoder(resp.Body).Decode(&supervision)
+	if err != nil {
+		return nil, err
+	}
+	if len(supervision.Embedded.State) == 0 {
+		return nil, fmt.Errorf("%s not found for %s", Resource, MechUnit)
+	}
+	return &supervision.Embedded.State[0], nil
+}
+
+// ApplyMotionSupervision sets both the motion supervision mode and sensitivity for a mechanical unit.
+// The current settings are read first and restored if any step fails.
+func (c *Client) ApplyMotionSupervision(Settings structures.MotionSupervision) error {
+	if Settings.Sensitivity < MinSupervisionLevel || Settings.Sensitivity > MaxSupervisionLevel {
+		return fmt.Errorf("invalid sensitivity %d", Settings.Sensitivity)
+	}
+	previous, err := c.GetMotionSupervision(Settings.MechUnit)
+	if err != nil {
+		return err
+	}
+	err = c.SetMotionSupervisionMode(Settings.MechUnit, Settings.Enabled)
+	if err != nil {
+		return err
+	}
+	err = c.SetMotionSupervisionSensitivity(Settings.MechUnit, Settings.Sensitivity)
+	if err != nil {
+		if revertErr := c.SetMotionSupervisionMode(previous.MechUnit, previous.Enabled); revertErr != nil {
+			return fmt.Errorf("%w (revert failed: %s)", err, revertErr)
+		}
+		return err
+	}
+	return nil
+}
+
+// ApplyPathSupervision sets both the path supervision mode and level for a mechanical unit.
+// The current settings are read first and restored if any step fails.
+func (c *Client) ApplyPathSupervision(Settings structures.PathSupervision) error {
+	if Settings.Level < MinSupervisionLevel || Settings.Level > MaxSupervisionLevel {
+		return fmt.Errorf("invalid level %d", Settings.Level)
+	}
+	previous, err := c.GetPathSupervision(Settings.MechUnit)
+	if err != nil {
+		return err
+	}
+	err = c.SetPathSupervisionMode(Settings.Enabled, Settings.MechUnit)
+	if err != nil {
+		return err
+	}
+	err = c.SetPathSupervisionLevel(Settings.Level, Settings.MechUnit)
+	if err != nil {
+		if revertErr := c.SetPathSupervisionMode(previous.Enabled, previous.MechUnit); revertErr != nil {
+			return fmt.Errorf("%w (revert failed: %s)", err, revertErr)
+		}
+		return err
+	}
+	return nil
+}
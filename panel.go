@@ -7,6 +7,7 @@ import (
 	"fmt"
 	"net/http"
 	"net/url"
+	"strconv"
 	"strings"
 	"time"
 
@@ -369,3 +370,62 @@ func (c *Client) SetSpeedRatio(SpeedRatio int8) error {
 	defer closeErrorCheck(resp.Body)
 	return nil
 }
+
+// GetControllerState returns the current state of the controller.
+// Possible values: {init | motoron | motoroff | guardstop | emergencystop | emergencystopreset | sysfail}
+func (c *Client) GetControllerState() (string, error) {
+	var ctrlstate structures.ControllerState
+	c.Client = c.DigestAuthenticate()
+	req, err := http.NewRequest("GET", "http://"+c.Host+"/rw/panel/ctrlstate", nil)
+	if err != nil {
+		return "", err
+	}
+	q := req.URL.Query()
+	q.Add("json", "1")
+	req.URL.RawQuery = q.Encode()
+	resp, err := c.Client.Do(req)
+	if err != nil {
+		return "", err
+	}
+	if resp.StatusCode != http.StatusOK {
+		return "", fmt.Errorf("HTTP Status Code: %d", resp.StatusCode)
+	}
+	defer closeErrorCheck(resp.Body)
+	err = json.NewDecoder(resp.Body).Decode(&ctrlstate)
+	if err != nil {
+		return "", err
+	}
+	if len(ctrlstate.Embedded.State) == 0 || ctrlstate.Embedded.State[0].CtrlState == "" {
+		return "", fmt.Errorf("Controller State Not Found: %v", ctrlstate)
+	}
+	return ctrlstate.Embedded.State[0].CtrlState, nil
+}
+
+// GetSpeedRatio returns the current speed ratio of the controller between 0 and 100.
+func (c *Client) GetSpeedRatio() (int, error) {
+	var speedRatio structures.SpeedRatio
+	c.Client = c.DigestAuthenticate()
+	req, err := http.NewRequest("GET", "http://"+c.Host+"/rw/panel/speedratio", nil)
+	if err != nil {
+		return 0, err
+	}
+	q := req.URL.Query()
+	q.Add("json", "1")
+	req.URL.RawQuery = q.Encode()
+	resp, err := c.Client.Do(req)
+	if err != nil {
+		return 0, err
+	}
+	if resp.StatusCode != http.StatusOK {
+		return 0, fmt.Errorf("HTTP Status Code: %d", resp.StatusCode)
+	}
+	defer closeErrorCheck(resp.Body)
+	err = json.NewDecoder(resp.Body).Decode(&speedRatio)
+	if err != nil {
+		return 0, err
+	}
+	if len(speedRatio.Embedded.State) == 0 {
+		return 0, fmt.Errorf("Speed Ratio Not Found: %v", speedRatio)
+	}
+	return strconv.Atoi(speedRatio.Embedded.State[0].SpeedRatio)
+}
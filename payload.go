@@ -0,0 +1,274 @@
+package abb
+
+import (
+	"encoding/json"
+	"fmt"
+	"math"
+	"net/http"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/atmassey/abb-lib-rws/structures"
+)
+
+// robotCapacity matches the handling capacity in a robot type, e.g. "IRB 120-3/0.6" -> 3
+var robotCapacity = regexp.MustCompile(`-(\d+(?:\.\d+)?)/`)
+
+// getMechUnit is a helper function that returns the properties of a single mechanical unit.
+func (c *Client) getMechUnit(Mechunit string) (*structures.MechUnitJsonMeta, error) {
+	var mechUnit structures.MechUnitJson
+	c.Client = c.DigestAuthenticate()
+	req, err := http.NewRequest("GET", "http://"+c.Host+"/rw/motionsystem/mechunits/"+Mechunit, nil)
+	if err != nil {
+		return nil, err
+	}
+	q := req.URL.Query()
+	q.Add("json", "1")
+	req.URL.RawQuery = q.Encode()
+	resp, err := c.Client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer closeErrorCheck(resp.Body)
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("HTTP Status: %s", resp.Status)
+	}
+	err = json.NewDecoder(resp.Body).Decode(&mechUnit)
+	if err != nil {
+		return nil, err
+	}
+	if len(mechUnit.Embedded.State) == 0 {
+		return nil, fmt.Errorf("mechunit not found: %s", Mechunit)
+	}
+	return &mechUnit.Embedded.State[0], nil
+}
+
+// GetCurrentTool returns the name of the tool currently active for a mechanical unit.
+func (c *Client) GetCurrentTool(Mechunit string) (string, error) {
+	mechUnit, err := c.getMechUnit(Mechunit)
+	if err != nil {
+		return "", err
+	}
+	return mechUnit.Tool, nil
+}
+
+// GetCurrentPayload returns the name of the payload currently active for a mechanical unit.
+func (c *Client) GetCurrentPayload(Mechunit string) (string, error) {
+	mechUnit, err := c.getMechUnit(Mechunit)
+	if err != nil {
+		return "", err
+	}
+	return mechUnit.Payload, nil
+}
+
+// GetToolData returns a decoded tooldata declared in RAPID.
+// Example: Task = T_ROB1, Module = user, Name = tool1
+func (c *Client) GetToolData(Task string, Module string, Name string) (*structures.ToolData, error) {
+	value, err := c.GetRapidSymbolValue(Task, Module, Name)
+	if err != nil {
+		return nil, err
+	}
+	tool, err := ParseToolData(value)
+	if err != nil {
+		return nil, fmt.Errorf("%s/%s/%s: %w", Task, Module, Name, err)
+	}
+	tool.Name = Name
+	tool.Task = Task
+	tool.Module = Module
+	return tool, nil
+}
+
+// GetLoadData returns a decoded loaddata declared in RAPID.
+// Example: Task = T_ROB1, Module = user, Name = load1
+func (c *Client) GetLoadData(Task string, Module string, Name string) (*structures.LoadData, error) {
+	value, err := c.GetRapidSymbolValue(Task, Module, Name)
+	if err != nil {
+		return nil, err
+	}
+	load, err := ParseLoadData(value)
+	if err != nil {
+		return nil, fmt.Errorf("%s/%s/%s: %w", Task, Module, Name, err)
+	}
+	return load, nil
+}
+
+// AuditPayloads reads every tooldata and loaddata declared in RAPID and validates them
+// against the robot type. Suspicious values are reported per task, as are symbols that
+// cannot be read or parsed.
+// The system defaults tool0 and load0 are skipped.
+func (c *Client) AuditPayloads() (*structures.PayloadAudit, error) {
+	audit := structures.PayloadAudit{
+		Loads:  make(map[string]structures.LoadData),
+		Issues: make(map[string][]structures.PayloadIssue),
+	}
+	robotType, err := c.GetRobotType()
+	if err != nil {
+		return nil, err
+	}
+	if len(robotType.Body.State.Robots) > 0 {
+		audit.RobotType = robotType.Body.State.Robots[0].RobotType
+		audit.Capacity = RobotCapacity(audit.RobotType)
+	}
+	for _, dataType := range []string{"tooldata", "loaddata"} {
+		for _, symbolType := range []string{"per", "con", "var"} {
+			symbols, err := c.SearchRapidSymbols(dataType, symbolType)
+			if err != nil {
+				return nil, err
+			}
+			for _, symbol := range symbols {
+				if symbol.Name == "tool0" || symbol.Name == "load0" {
+					continue
+				}
+				var load structures.LoadData
+				if dataType == "tooldata" {
+					tool, err := c.GetToolData(symbol.Task, symbol.Module, symbol.Name)
+					if err != nil {
+						addPayloadIssue(&audit, symbol, dataType, "unable to read: "+err.Error())
+						continue
+					}
+					audit.Tools = append(audit.Tools, *tool)
+					load = tool.TLoad
+				} else {
+					loadData, err := c.GetLoadData(symbol.Task, symbol.Module, symbol.Name)
+					if err != nil {
+						addPayloadIssue(&audit, symbol, dataType, "unable to read: "+err.Error())
+						continue
+					}
+					audit.Loads[symbol.Task+"/"+symbol.Module+"/"+symbol.Name] = *loadData
+					load = *loadData
+				}
+				for _, issue := range ValidateLoadData(load, audit.Capacity) {
+					addPayloadIssue(&audit, symbol, dataType, issue)
+				}
+			}
+		}
+	}
+	return &audit, nil
+}
+
+// addPayloadIssue records an issue of a tooldata or loaddata symbol under its task.
+func addPayloadIssue(Audit *structures.PayloadAudit, Symbol structures.RapidSymbol, DataType string, Issue string) {
+	Audit.Issues[Symbol.Task] = append(Audit.Issues[Symbol.Task], structures.PayloadIssue{
+		Task:     Symbol.Task,
+		Module:   Symbol.Module,
+		Name:     Symbol.Name,
+		DataType: DataType,
+		Issue:    Issue,
+	})
+}
+
+// RobotCapacity returns the handling capacity in kg of a robot type, or 0 if it can't be determined.
+// Example: IRB 120-3/0.6 -> 3
+func RobotCapacity(RobotType string) float64 {
+	match := robotCapacity.FindStringSubmatch(RobotType)
+	if match == nil {
+		return 0
+	}
+	capacity, err := strconv.ParseFloat(match[1], 64)
+	if err != nil {
+		return 0
+	}
+	return capacity
+}
+
+// ValidateLoadData returns a list of suspicious values in a loaddata.
+// Capacity is the handling capacity of the robot in kg, a capacity of 0 skips the mass limit check.
+func ValidateLoadData(Load structures.LoadData, Capacity float64) []string {
+	var issues []string
+	switch {
+	case Load.Mass < 0:
+		issues = append(issues, fmt.Sprintf("negative mass %g kg", Load.Mass))
+	case Load.Mass == 0:
+		issues = append(issues, "zero mass")
+	case Capacity > 0 && Load.Mass > Capacity:
+		issues = append(issues, fmt.Sprintf("mass %g kg exceeds robot capacity %g kg", Load.Mass, Capacity))
+	}
+	if Load.Mass > 0 && Load.CoG == [3]float64{} {
+		issues = append(issues, "default center of gravity")
+	}
+	if Load.Ix < 0 || Load.Iy < 0 || Load.Iz < 0 {
+		issues = append(issues, "negative moment of inertia")
+	}
+	if Load.Mass > 0 && Load.Ix == 0 && Load.Iy == 0 && Load.Iz == 0 {
+		issues = append(issues, "default inertia")
+	}
+	norm := math.Sqrt(Load.AoM[0]*Load.AoM[0] + Load.AoM[1]*Load.AoM[1] + Load.AoM[2]*Load.AoM[2] + Load.AoM[3]*Load.AoM[3])
+	if math.Abs(norm-1) > 0.01 {
+		issues = append(issues, "axes of moment is not a normalized quaternion")
+	}
+	return issues
+}
+
+// ParseToolData decodes a RAPID tooldata value.
+// Example: [TRUE,[[0,0,100],[1,0,0,0]],[1,[0,0,1],[1,0,0,0],0,0,0]]
+func ParseToolData(Value string) (*structures.ToolData, error) {
+	fields := flattenRapidValue(Value)
+	if len(fields) != 19 {
+		return nil, fmt.Errorf("invalid tooldata: %s", Value)
+	}
+	var tool structures.ToolData
+	switch strings.ToUpper(fields[0]) {
+	case "TRUE":
+		tool.RobHold = true
+	case "FALSE":
+		tool.RobHold = false
+	default:
+		return nil, fmt.Errorf("invalid robhold: %s", fields[0])
+	}
+	numbers, err := parseRapidNumbers(fields[1:])
+	if err != nil {
+		return nil, err
+	}
+	copy(tool.TFrame.Trans[:], numbers[0:3])
+	copy(tool.TFrame.Rot[:], numbers[3:7])
+	tool.TLoad = loadDataFromNumbers(numbers[7:])
+	return &tool, nil
+}
+
+// ParseLoadData decodes a RAPID loaddata value.
+// Example: [1,[0,0,1],[1,0,0,0],0,0,0]
+func ParseLoadData(Value string) (*structures.LoadData, error) {
+	fields := flattenRapidValue(Value)
+	if len(fields) != 11 {
+		return nil, fmt.Errorf("invalid loaddata: %s", Value)
+	}
+	numbers, err := parseRapidNumbers(fields)
+	if err != nil {
+		return nil, err
+	}
+	load := loadDataFromNumbers(numbers)
+	return &load, nil
+}
+
+func loadDataFromNumbers(Numbers []float64) structures.LoadData {
+	var load structures.LoadData
+	load.Mass = Numbers[0]
+	copy(load.CoG[:], Numbers[1:4])
+	copy(load.AoM[:], Numbers[4:8])
+	load.Ix = Numbers[8]
+	load.Iy = Numbers[9]
+	load.Iz = Numbers[10]
+	return load
+}
+
+// flattenRapidValue splits a RAPID record value into its scalar fields.
+func flattenRapidValue(Value string) []string {
+	value := strings.NewReplacer("[", "", "]", "", " ", "").Replace(Value)
+	if value == "" {
+		return nil
+	}
+	return strings.Split(value, ",")
+}
+
+func parseRapidNumbers(Fields []string) ([]float64, error) {
+	numbers := make([]float64, 0, len(Fields))
+	for _, field := range Fields {
+		number, err := strconv.ParseFloat(field, 64)
+		if err != nil {
+			return nil, fmt.Errorf("invalid number: %s", field)
+		}
+		numbers = append(numbers, number)
+	}
+	return numbers, nil
+}
@@ -3,12 +3,16 @@ package abb
 import (
 	"fmt"
 	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/atmassey/abb-lib-rws/structures"
 )
 
 // ClearProfinetAlarms clears the alarms for a specific profinet device
 func (c *Client) ClearProfinetAlarms(Device string, Network string) error {
 	c.Client = c.DigestAuthenticate()
-	req, err := http.NewRequest("POST", "http://"+c.Host+"rw/iosystem/devices/"+Network+"/"+Device+"/alarms/clear", nil)
+	req, err := http.NewRequest("POST", "http://"+c.Host+"/rw/iosystem/devices/"+Network+"/"+Device+"/alarms/clear", nil)
 	if err != nil {
 		return err
 	}
@@ -17,8 +21,114 @@ func (c *Client) ClearProfinetAlarms(Device string, Network string) error {
 	if err != nil {
 		return err
 	}
+	defer closeErrorCheck(resp.Body)
 	if resp.StatusCode != http.StatusOK {
 		return fmt.Errorf("HTTP Status: %s", resp.Status)
 	}
 	return nil
 }
+
+// GetProfinetDevices returns the profinet devices on a network with their station name, IP address and state.
+// Example network: PROFINET
+func (c *Client) GetProfinetDevices(Network string) ([]structures.ProfinetDevice, error) {
+	devices, err := c.GetIODevices(Network)
+	if err != nil {
+		return nil, err
+	}
+	var profinetDevices []structures.ProfinetDevice
+	for _, device := range devices {
+		var detail structures.ProfinetDeviceJson
+		_, err := c.getJSONPage("http://"+c.Host+"/rw/iosystem/devices/"+Network+"/"+device.Name+"?json=1", &detail)
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", device.Path, err)
+		}
+		profinetDevice := structures.ProfinetDevice{
+			Network: Network,
+			Name:    device.Name,
+			State:   device.PState,
+			Enabled: device.Enabled,
+		}
+		if len(detail.Embedded.State) > 0 {
+			state := detail.Embedded.State[0]
+			profinetDevice.StationName = state.StationName
+			profinetDevice.IPAddress = state.IPAddress
+			profinetDevice.DeviceID = state.DeviceID
+			profinetDevice.VendorID = state.VendorID
+		}
+		if profinetDevice.IPAddress == "" {
+			profinetDevice.IPAddress = device.Address
+		}
+		profinetDevices = append(profinetDevices, profinetDevice)
+	}
+	return profinetDevices, nil
+}
+
+// GetProfinetAlarms returns the active alarms of a profinet device with slot, subslot and channel diagnostics.
+func (c *Client) GetProfinetAlarms(Device string, Network string) ([]structures.ProfinetAlarm, error) {
+	var alarms []structures.ProfinetAlarm
+	next := "http://" + c.Host + "/rw/iosystem/devices/" + Network + "/" + Device + "/alarms?json=1"
+	for next != "" {
+		var page structures.ProfinetAlarmsJson
+		var err error
+		next, err = c.getJSONPage(next, &page)
+		if err != nil {
+			return nil, err
+		}
+		for _, state := range page.Embedded.State {
+			alarm, err := profinetAlarmFromJson(state)
+			if err != nil {
+				return nil, err
+			}
+			alarms = append(alarms, alarm)
+		}
+	}
+	return alarms, nil
+}
+
+// profinetAlarmFromJson decodes a single alarm. Numbers are hexadecimal with a 0x prefix, e.g. the
+// subslot 0x8001, and decimal otherwise, so zero padded values like 08 stay decimal.
+// An alarm without a time keeps the zero time.
+func profinetAlarmFromJson(State structures.ProfinetAlarmsJsonMeta) (structures.ProfinetAlarm, error) {
+	alarm := structures.ProfinetAlarm{
+		AlarmType: State.AlarmType,
+		Severity:  State.Severity,
+		Text:      State.Text,
+	}
+	fields := []struct {
+		name  string
+		value string
+		dest  *int
+	}{
+		{"slot", State.Slot, &alarm.Slot},
+		{"subslot", State.Subslot, &alarm.Subslot},
+		{"channel", State.Channel, &alarm.Channel},
+		{"channel-error-type", State.ChannelError, &alarm.ChannelErrorType},
+		{"ext-channel-error-type", State.ExtendedError, &alarm.ExtendedChannelErrorType},
+	}
+	for _, field := range fields {
+		if field.value == "" {
+			continue
+		}
+		value, err := parseAlarmNumber(field.value)
+		if err != nil {
+			return alarm, fmt.Errorf("invalid %s %q in alarm %s: %w", field.name, field.value, State.Title, err)
+		}
+		*field.dest = int(value)
+	}
+	if State.Time != "" {
+		var err error
+		alarm.Time, err = parseRWSTime(State.Time)
+		if err != nil {
+			return alarm, fmt.Errorf("invalid time %q in alarm %s: %w", State.Time, State.Title, err)
+		}
+	}
+	return alarm, nil
+}
+
+// parseAlarmNumber parses a hexadecimal number with a 0x prefix or a decimal number.
+func parseAlarmNumber(Value string) (int64, error) {
+	if strings.HasPrefix(Value, "0x") || strings.HasPrefix(Value, "0X") {
+		return strconv.ParseInt(Value[2:], 16, 32)
+	}
+	return strconv.ParseInt(Value, 10, 32)
+}
@@ -0,0 +1,94 @@
+package abb
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+
+	"github.com/atmassey/abb-lib-rws/structures"
+)
+
+// GetRapidSymbolValue returns the raw value of a RAPID data symbol.
+// Example: Task = T_ROB1, Module = user, Name = tool1 -> [TRUE,[[0,0,100],[1,0,0,0]],[1,[0,0,1],[1,0,0,0],0,0,0]]
+func (c *Client) GetRapidSymbolValue(Task string, Module string, Name string) (string, error) {
+	var value structures.RapidSymbolValueJson
+	c.Client = c.DigestAuthenticate()
+	req, err := http.NewRequest("GET", "http://"+c.Host+"/rw/rapid/symbol/data/RAPID/"+Task+"/"+Module+"/"+Name, nil)
+	if err != nil {
+		return "", err
+	}
+	q := req.URL.Query()
+	q.Add("json", "1")
+	req.URL.RawQuery = q.Encode()
+	resp, err := c.Client.Do(req)
+	if err != nil {
+		return "", err
+	}
+	defer closeErrorCheck(resp.Body)
+	if resp.StatusCode != http.StatusOK {
+		return "", fmt.Errorf("HTTP Status Code: %d", resp.StatusCode)
+	}
+	err = json.NewDecoder(resp.Body).Decode(&value)
+	if err != nil {
+		return "", err
+	}
+	if len(value.Embedded.State) == 0 {
+		return "", fmt.Errorf("symbol not found: %s/%s/%s", Task, Module, Name)
+	}
+	return value.Embedded.State[0].Value, nil
+}
+
+// SearchRapidSymbols returns all RAPID data symbols of the given data type in all tasks.
+// SymbolType can be "per", "con" or "var".
+// Example: DataType = tooldata, SymbolType = per
+func (c *Client) SearchRapidSymbols(DataType string, SymbolType string) ([]structures.RapidSymbol, error) {
+	var symbols []structures.RapidSymbol
+	body := url.Values{}
+	body.Add("view", "block")
+	body.Add("blockurl", "RAPID")
+	body.Add("recursive", "TRUE")
+	body.Add("symtyp", SymbolType)
+	body.Add("dattyp", DataType)
+	next := "http://" + c.Host + "/rw/rapid/symbols?action=search-symbols&json=1"
+	for next != "" {
+		var symbolsRaw structures.RapidSymbolsJson
+		c.Client = c.DigestAuthenticate()
+		req, err := http.NewRequest("POST", next, bytes.NewBufferString(body.Encode()))
+		if err != nil {
+			return nil, err
+		}
+		req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+		resp, err := c.Client.Do(req)
+		if err != nil {
+			return nil, err
+		}
+		if resp.StatusCode != http.StatusOK {
+			closeErrorCheck(resp.Body)
+			return nil, fmt.Errorf("HTTP Status Code: %d", resp.StatusCode)
+		}
+		err = json.NewDecoder(resp.Body).Decode(&symbolsRaw)
+		closeErrorCheck(resp.Body)
+		if err != nil {
+			return nil, err
+		}
+		for _, symbol := range symbolsRaw.Embedded.State {
+			// symburl is RAPID/<task>/<module>/<name>
+			parts := strings.Split(symbol.SymbUrl, "/")
+			if len(parts) != 4 {
+				continue
+			}
+			symbols = append(symbols, structures.RapidSymbol{
+				Name:     symbol.Name,
+				Task:     parts[1],
+				Module:   parts[2],
+				SymbType: symbol.SymbType,
+				DataType: symbol.DataType,
+			})
+		}
+		next = nextPageURL(next, symbolsRaw.Links.Base.Href, symbolsRaw.Links.Next.Href)
+	}
+	return symbols, nil
+}
@@ -0,0 +1,115 @@
+package structures
+
+type CfgJsonLinks struct {
+	Base CfgJsonHref `json:"base"`
+	Next CfgJsonHref `json:"next"`
+}
+
+type CfgJsonHref struct {
+	Href string `json:"href"`
+}
+
+type CfgInstanceJson struct {
+	Links    CfgJsonLinks         `json:"_links"`
+	Embedded CfgInstanceJsonState `json:"_embedded"`
+}
+
+type CfgInstanceJsonState struct {
+	State []CfgInstanceJsonMeta `json:"_state"`
+}
+
+type CfgInstanceJsonMeta struct {
+	Type   string                 `json:"_type"`
+	Title  string                 `json:"_title"`
+	Attrib []CfgAttributeJsonMeta `json:"attrib"`
+}
+
+type CfgAttributeJsonMeta struct {
+	Type  string `json:"_type"`
+	Title string `json:"_title"`
+	Value string `json:"value"`
+}
+
+type CfgInstancesJson struct {
+	Links    CfgJsonLinks         `json:"_links"`
+	Embedded CfgInstanceJsonState `json:"_embedded"`
+}
+
+type CfgListJson struct {
+	Links    CfgJsonLinks     `json:"_links"`
+	Embedded CfgListJsonState `json:"_embedded"`
+}
+
+type CfgListJsonState struct {
+	State []CfgListJsonMeta `json:"_state"`
+}
+
+type CfgListJsonMeta struct {
+	Type  string `json:"_type"`
+	Title string `json:"_title"`
+}
+
+// CfgAttributes maps the attribute names of a configuration instance to their values.
+type CfgAttributes map[string]string
+
+type CfgInstance struct {
+	Domain     string
+	Type       string
+	Name       string
+	Attributes CfgAttributes
+}
+
+// CfgRejectedInstance is an instance of a loaded configuration file that the controller did not accept.
+type CfgRejectedInstance struct {
+	Type   string
+	Name   string
+	Reason string
+}
+
+// CfgLoadResult is the outcome of loading a configuration file.
+type CfgLoadResult struct {
+	Domain   string
+	Mode     string
+	Accepted int
+	// Skipped is the number of instances that already existed on the controller and were
+	// kept unchanged because the file was loaded in add mode.
+	Skipped  int
+	Rejected []CfgRejectedInstance
+	// RestartRequired is set when the load changed the configuration, the changes
+	// take effect after a warm start.
+	RestartRequired bool
+}
+
+// Kinds of configuration changes.
+const (
+	CfgAdded   = "added"
+	CfgRemoved = "removed"
+	CfgChanged = "changed"
+	// CfgDuplicate reports an instance name used more than once within a type,
+	// A and B hold the number of definitions in each configuration.
+	CfgDuplicate = "duplicate"
+)
+
+// CfgChange is a single difference between two configurations. Attribute is empty when a whole
+// domain, type or instance was added or removed.
+type CfgChange struct {
+	Kind      string
+	Domain    string
+	Type      string
+	Instance  string
+	Attribute string
+	A         string
+	B         string
+}
+
+// ConfigDiff is the difference between two configurations, A and B name the compared sources.
+type ConfigDiff struct {
+	A       string
+	B       string
+	Changes []CfgChange
+}
+
+// Equal reports whether both configurations are the same.
+func (d *ConfigDiff) Equal() bool {
+	return len(d.Changes) == 0
+}
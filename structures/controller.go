@@ -119,3 +119,42 @@ type Clock struct {
 	Minute string
 	Second string
 }
+
+type RMMPState struct {
+	Links    UserResourcesLinksJson `json:"_links"`
+	Embedded RMMPStateEmbedded      `json:"_embedded"`
+}
+
+type UserResourcesLinksJson struct {
+	Base UserResourcesBaseJson `json:"base"`
+}
+
+type UserResourcesBaseJson struct {
+	Href string `json:"href"`
+}
+
+type RMMPStateEmbedded struct {
+	State []RMMPStateMeta `json:"_state"`
+}
+
+type RMMPStateMeta struct {
+	Type      string `json:"_type"`
+	Title     string `json:"_title"`
+	Privilege string `json:"privilege"`
+	RMMPHeld  string `json:"rmmpheldbyme"`
+}
+
+type ControllerLanguagesJson struct {
+	Links    UserResourcesLinksJson      `json:"_links"`
+	Embedded ControllerLanguagesEmbedded `json:"_embedded"`
+}
+
+type ControllerLanguagesEmbedded struct {
+	State []ControllerLanguageMeta `json:"_state"`
+}
+
+type ControllerLanguageMeta struct {
+	Type  string `json:"_type"`
+	Title string `json:"_title"`
+	Lang  string `json:"lang"`
+}
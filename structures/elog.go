@@ -1,6 +1,9 @@
 package structures
 
-import "encoding/xml"
+import (
+	"encoding/xml"
+	"time"
+)
 
 type ElogXML struct {
 	XMLName xml.Name `xml:"html"`
@@ -50,8 +53,14 @@ type ElogMessagesXML struct {
 }
 
 type ElogMessageHead struct {
-	Title string `xml:"title"`
-	Base  string `xml:"base,attr"`
+	Title    string       `xml:"title"`
+	Base     string       `xml:"base,attr"`
+	BaseLink ElogBaseLink `xml:"base"`
+}
+
+// ElogBaseLink is the base element of a response, paging links are relative to it.
+type ElogBaseLink struct {
+	Href string `xml:"href,attr"`
 }
 
 type ElogMessageBody struct {
@@ -79,3 +88,149 @@ type ElogMessageSpan struct {
 	Class string `xml:"class,attr"`
 	Text  string `xml:",chardata"`
 }
+
+type ElogDomainMessagesXML struct {
+	XMLName xml.Name               `xml:"html"`
+	Head    ElogMessageHead        `xml:"head"`
+	Body    ElogDomainMessagesBody `xml:"body"`
+}
+
+type ElogDomainMessagesBody struct {
+	Div ElogDomainMessagesDiv `xml:"div"`
+}
+
+type ElogDomainMessagesDiv struct {
+	Class string              `xml:"class,attr"`
+	Links []ElogMessageLink   `xml:"a"`
+	List  []ElogDomainMessage `xml:"ul>li"`
+}
+
+type ElogDomainMessage struct {
+	Class string            `xml:"class,attr"`
+	Title string            `xml:"title,attr"`
+	Link  ElogMessageLink   `xml:"a"`
+	Span  []ElogMessageSpan `xml:"span"`
+}
+
+// ElogMessage is a decoded event log message.
+type ElogMessage struct {
+	Domain       int
+	SeqNum       int
+	MsgType      int
+	Code         int
+	Timestamp    time.Time
+	Title        string
+	Description  string
+	Consequences string
+	Causes       string
+	Actions      string
+	Args         []string
+}
+
+// ElogPosition identifies the last processed message of an Elog domain. The timestamp detects a cleared
+// Elog or wrapped sequence numbers, where new messages get lower sequence numbers than processed ones.
+type ElogPosition struct {
+	SeqNum int       `json:"seqnum"`
+	Time   time.Time `json:"time"`
+}
+
+// Covers reports whether a message of the domain was already processed up to this position. A message
+// with a lower sequence number but a newer timestamp is new, the Elog was cleared or the numbers wrapped.
+// A position without a timestamp compares the sequence numbers only.
+func (p ElogPosition) Covers(Message ElogMessage) bool {
+	switch {
+	case p.Time.IsZero():
+		return Message.SeqNum <= p.SeqNum
+	case Message.SeqNum > p.SeqNum:
+		// a higher sequence number logged before the position predates a clear or wrap
+		return Message.Timestamp.Before(p.Time)
+	default:
+		return !Message.Timestamp.After(p.Time)
+	}
+}
+
+type CollisionEvent struct {
+	MechUnit string
+	Time     time.Time
+	// State is the collision detection state of the controller.
+	// Possible values: {INIT | TRIGGERED | CONFIRMED | TRIGGERED_ACK | UNDEFINED}
+	State string
+	// Code and Title are set when the event originates from a collision elog message.
+	Code  int
+	Title string
+}
+
+type Collision struct {
+	MechUnit string
+	Time     time.Time
+	Code     int
+	Title    string
+	// RecoveryState is the current collision detection state for the newest collision.
+	// It is empty for older collisions since the controller does not keep their recovery state.
+	RecoveryState string
+}
+
+type ElogDomainsXML struct {
+	XMLName xml.Name        `xml:"html"`
+	Head    ElogMessageHead `xml:"head"`
+	Body    ElogDomainsBody `xml:"body"`
+}
+
+type ElogDomainsBody struct {
+	Div ElogDomainsDiv `xml:"div"`
+}
+
+type ElogDomainsDiv struct {
+	Class string              `xml:"class,attr"`
+	Links []ElogMessageLink   `xml:"a"`
+	List  []ElogDomainMessage `xml:"ul>li"`
+}
+
+// ElogDomain is an event log domain, e.g. 0 Common or 1 Operational.
+type ElogDomain struct {
+	Number     int
+	Name       string
+	BufferSize int
+	// Messages is the number of messages currently in the domain.
+	Messages int
+}
+
+// Elog message types.
+const (
+	ElogInfo    = 1
+	ElogWarning = 2
+	ElogError   = 3
+)
+
+// ElogTypeName returns the name of an Elog message type, e.g. 3 -> error.
+func ElogTypeName(MsgType int) string {
+	switch MsgType {
+	case ElogInfo:
+		return "info"
+	case ElogWarning:
+		return "warning"
+	case ElogError:
+		return "error"
+	default:
+		return "unknown"
+	}
+}
+
+// ElogCodeRange is an inclusive range of elog codes, e.g. 50000-50999 for motion messages.
+type ElogCodeRange struct {
+	Min int
+	Max int
+}
+
+// ElogFilter selects elog messages. Zero values don't filter.
+type ElogFilter struct {
+	// Types are the message types to return, see ElogInfo, ElogWarning and ElogError.
+	Types []int
+	Codes []ElogCodeRange
+	Since time.Time
+	Until time.Time
+	// AfterSeqNum returns only messages with a higher sequence number.
+	AfterSeqNum int
+	// Limit is the maximum number of messages to return.
+	Limit int
+}
@@ -1,6 +1,9 @@
 package structures
 
-import "encoding/xml"
+import (
+	"encoding/xml"
+	"time"
+)
 
 type IOSignalsJson struct {
 	Links    IOSignalsJsonLinks `json:"_links"`
@@ -82,3 +85,257 @@ type IOSignalSpan struct {
 	Class string `xml:"class,attr"`
 	Text  string `xml:",chardata"`
 }
+
+type IOSignalJson struct {
+	Links    IOSignalsJsonLinks `json:"_links"`
+	Embedded IOSignalJsonState  `json:"_embedded"`
+}
+
+type IOSignalJsonState struct {
+	State []IOSignalJsonMeta `json:"_state"`
+}
+
+type IOSignalJsonMeta struct {
+	TypeT    string `json:"_type"`
+	Title    string `json:"_title"`
+	Name     string `json:"name"`
+	Type     string `json:"type"`
+	Category string `json:"category"`
+	LValue   string `json:"lvalue"`
+	LState   string `json:"lstate"`
+	Quality  string `json:"quality"`
+	Time     string `json:"time"`
+}
+
+// Signal is a single IO signal with its typed value.
+type Signal struct {
+	Path     string
+	Name     string
+	Type     string
+	Category string
+	// LValue is the logical value as reported by the controller.
+	LValue string
+	// Value is the logical value as bool for DI/DO, float64 for AI/AO and uint64 for GI/GO.
+	// It is nil when LValue cannot be typed, like the empty value of an unmapped signal.
+	Value   interface{}
+	LState  string
+	Quality string
+}
+
+// SignalLimits are the configured logical limits of an analog or group signal.
+type SignalLimits struct {
+	MinLog float64
+	MaxLog float64
+	// BitLength is the number of bits in the device map of the signal, 0 if the signal is not mapped.
+	BitLength int
+}
+
+type IOSignalsPageJson struct {
+	Links    IOSignalsPageJsonLinks `json:"_links"`
+	Embedded IOSignalJsonState      `json:"_embedded"`
+}
+
+type IOSignalsPageJsonLinks struct {
+	Base IOSignalsJsonBase `json:"base"`
+	Next IOSignalsJsonBase `json:"next"`
+}
+
+type IONetworksJson struct {
+	Links    IOSignalsPageJsonLinks `json:"_links"`
+	Embedded IONetworksJsonState    `json:"_embedded"`
+}
+
+type IONetworksJsonState struct {
+	State []IONetworksJsonMeta `json:"_state"`
+}
+
+type IONetworksJsonMeta struct {
+	Type   string `json:"_type"`
+	Title  string `json:"_title"`
+	Name   string `json:"name"`
+	PState string `json:"pstate"`
+	LState string `json:"lstate"`
+}
+
+type IODevicesJson struct {
+	Links    IOSignalsPageJsonLinks `json:"_links"`
+	Embedded IODevicesJsonState     `json:"_embedded"`
+}
+
+type IODevicesJsonState struct {
+	State []IODevicesJsonMeta `json:"_state"`
+}
+
+type IODevicesJsonMeta struct {
+	Type      string `json:"_type"`
+	Title     string `json:"_title"`
+	Name      string `json:"name"`
+	PState    string `json:"pstate"`
+	LState    string `json:"lstate"`
+	Address   string `json:"address"`
+	VendorID  string `json:"vendor-id"`
+	ProductID string `json:"product-id"`
+}
+
+type IONetwork struct {
+	Name string
+	// LState is the logical state of the network, e.g. started or stopped.
+	LState string
+	// PState is the physical state of the network, e.g. running or error.
+	PState string
+}
+
+type IODevice struct {
+	Network string
+	Name    string
+	// Path is the device path used by UpdateIODevice, e.g. Local/DRV_1.
+	Path      string
+	LState    string
+	PState    string
+	Address   string
+	VendorID  string
+	ProductID string
+	Enabled   bool
+}
+
+type IODeviceNode struct {
+	Device  IODevice
+	Signals []Signal
+}
+
+type IONetworkNode struct {
+	Network IONetwork
+	Devices []IODeviceNode
+}
+
+type IODeviceTree struct {
+	Networks []IONetworkNode
+	// Unmapped holds the signals that are not connected to a device, sorted by path.
+	Unmapped []Signal
+}
+
+// SignalFilter selects signals in QuerySignals. Empty fields match all signals.
+type SignalFilter struct {
+	Network  string
+	Device   string
+	Type     string
+	Category string
+	// NamePattern is a shell pattern matched against the signal name, e.g. "do_Paint_*".
+	NamePattern string
+}
+
+type SignalSet struct {
+	Signals []Signal
+	// ByName maps the signal name to its entry in Signals.
+	ByName map[string]*Signal
+}
+
+type CrossConnectionActor struct {
+	Signal string
+	Invert bool
+	// Operator combines this actor with the next one, either AND or OR. Empty for the last actor.
+	Operator string
+}
+
+type CrossConnection struct {
+	Name   string
+	Result string
+	Actors []CrossConnectionActor
+}
+
+// SignalInfo is the full configuration of an IO signal.
+type SignalInfo struct {
+	Path        string
+	Name        string
+	Type        string
+	Device      string
+	DeviceMap   string
+	BitLength   int
+	Category    string
+	AccessLevel string
+	Default     string
+	MinLog      float64
+	MaxLog      float64
+	MinPhys     float64
+	MaxPhys     float64
+	MinBitVal   float64
+	MaxBitVal   float64
+	Invert      bool
+	// CrossConnections are the cross connections in which the signal is a result or an actor.
+	CrossConnections []CrossConnection
+}
+
+type CrossConnectionGraph struct {
+	Connections []CrossConnection
+	// Adjacency maps every actor signal to the result signals it drives.
+	Adjacency map[string][]string
+}
+
+type ProfinetDeviceJson struct {
+	Links    IOSignalsPageJsonLinks  `json:"_links"`
+	Embedded ProfinetDeviceJsonState `json:"_embedded"`
+}
+
+type ProfinetDeviceJsonState struct {
+	State []ProfinetDeviceJsonMeta `json:"_state"`
+}
+
+type ProfinetDeviceJsonMeta struct {
+	Type        string `json:"_type"`
+	Title       string `json:"_title"`
+	Name        string `json:"name"`
+	LState      string `json:"lstate"`
+	PState      string `json:"pstate"`
+	StationName string `json:"station-name"`
+	IPAddress   string `json:"ip-address"`
+	DeviceID    string `json:"device-id"`
+	VendorID    string `json:"vendor-id"`
+}
+
+type ProfinetDevice struct {
+	Network     string
+	Name        string
+	StationName string
+	IPAddress   string
+	// State is the physical state of the device, e.g. running or error.
+	State    string
+	Enabled  bool
+	DeviceID string
+	VendorID string
+}
+
+type ProfinetAlarmsJson struct {
+	Links    IOSignalsPageJsonLinks  `json:"_links"`
+	Embedded ProfinetAlarmsJsonState `json:"_embedded"`
+}
+
+type ProfinetAlarmsJsonState struct {
+	State []ProfinetAlarmsJsonMeta `json:"_state"`
+}
+
+type ProfinetAlarmsJsonMeta struct {
+	Type          string `json:"_type"`
+	Title         string `json:"_title"`
+	AlarmType     string `json:"alarm-type"`
+	Slot          string `json:"slot"`
+	Subslot       string `json:"subslot"`
+	Channel       string `json:"channel"`
+	ChannelError  string `json:"channel-error-type"`
+	ExtendedError string `json:"ext-channel-error-type"`
+	Severity      string `json:"severity"`
+	Time          string `json:"time"`
+	Text          string `json:"text"`
+}
+
+type ProfinetAlarm struct {
+	AlarmType string
+	Slot      int
+	Subslot   int
+	Channel   int
+	// ChannelErrorType is the PROFINET channel diagnostic error type, e.g. 1 = short circuit.
+	ChannelErrorType         int
+	ExtendedChannelErrorType int
+	Severity                 string
+	Time                     time.Time
+	Text                     string
+}
@@ -0,0 +1,54 @@
+package structures
+
+import "time"
+
+// IOTestPlan is a declarative IO checkout plan loaded from JSON or YAML.
+type IOTestPlan struct {
+	Name  string       `json:"name" yaml:"name"`
+	Steps []IOTestStep `json:"steps" yaml:"steps"`
+}
+
+// IOTestStep drives one signal and verifies the expected loop-back signals.
+type IOTestStep struct {
+	Name string `json:"name" yaml:"name"`
+	// Signal is the signal driven by the step. Outputs are written, inputs must be simulated.
+	Signal string `json:"signal" yaml:"signal"`
+	Value  string `json:"value" yaml:"value"`
+	// Simulate drives the signal through simulation instead of an output write.
+	Simulate bool                `json:"simulate" yaml:"simulate"`
+	Expect   []IOTestExpectation `json:"expect" yaml:"expect"`
+	// TimeoutMs is the time the expectations have to be met in, 1000 ms if not set.
+	TimeoutMs int `json:"timeout_ms" yaml:"timeout_ms"`
+}
+
+type IOTestExpectation struct {
+	Signal string `json:"signal" yaml:"signal"`
+	Value  string `json:"value" yaml:"value"`
+}
+
+type IOTestExpectationResult struct {
+	Signal   string `json:"signal"`
+	Expected string `json:"expected"`
+	Actual   string `json:"actual"`
+	Passed   bool   `json:"passed"`
+}
+
+type IOTestStepResult struct {
+	Name         string                    `json:"name"`
+	Signal       string                    `json:"signal"`
+	Value        string                    `json:"value"`
+	Passed       bool                      `json:"passed"`
+	ResponseTime time.Duration             `json:"response_time_ns"`
+	Error        string                    `json:"error,omitempty"`
+	Expectations []IOTestExpectationResult `json:"expectations"`
+}
+
+type IOTestReport struct {
+	Plan      string             `json:"plan"`
+	StartTime time.Time          `json:"start_time"`
+	EndTime   time.Time          `json:"end_time"`
+	Passed    bool               `json:"passed"`
+	Steps     []IOTestStepResult `json:"steps"`
+	// CleanupError is set when the signals could not be restored after the test.
+	CleanupError string `json:"cleanup_error,omitempty"`
+}
@@ -1,6 +1,9 @@
 package structures
 
-import "encoding/xml"
+import (
+	"encoding/xml"
+	"time"
+)
 
 type MotionErrorStateJson struct {
 	Links    MotionErrorStateJsonLinks `json:"_links"`
@@ -206,3 +209,137 @@ type AxisPositon struct {
 	Q3 string
 	Q4 string
 }
+
+type AxisCalibrationJson struct {
+	Links    MechUnitsJsonLinks       `json:"_links"`
+	Embedded AxisCalibrationJsonState `json:"_embedded"`
+}
+
+type AxisCalibrationJsonState struct {
+	State []AxisCalibrationJsonMeta `json:"_state"`
+}
+
+type AxisCalibrationJsonMeta struct {
+	Type        string `json:"_type"`
+	Title       string `json:"_title"`
+	CalibStatus string `json:"calib-status"`
+	CalibOffset string `json:"calib-offset"`
+	CommOffset  string `json:"comm-offset"`
+}
+
+type AxisCalibration struct {
+	Axis              int
+	Status            string
+	CalibOffset       string
+	CommutationOffset string
+}
+
+type AxisCalibrationResult struct {
+	Axis        int
+	Action      string
+	Error       string
+	Calibration *AxisCalibration
+}
+
+type CalibrationReport struct {
+	MechUnit      string
+	Method        string
+	OperationMode string
+	StartTime     time.Time
+	EndTime       time.Time
+	Axes          []AxisCalibrationResult
+}
+
+// JogIncrements holds the jog values for a single jog request in percent (-100 to 100).
+// For axis jogging Axis1-Axis6 are the robot axes, for cartesian jogging they are
+// x, y, z, rx, ry and rz in the selected coordinate system.
+type JogIncrements struct {
+	Axis1 int
+	Axis2 int
+	Axis3 int
+	Axis4 int
+	Axis5 int
+	Axis6 int
+}
+
+type ChangeCountJson struct {
+	Links    MechUnitsJsonLinks   `json:"_links"`
+	Embedded ChangeCountJsonState `json:"_embedded"`
+}
+
+type ChangeCountJsonState struct {
+	State []ChangeCountJsonMeta `json:"_state"`
+}
+
+type ChangeCountJsonMeta struct {
+	Type        string `json:"_type"`
+	Title       string `json:"_title"`
+	ChangeCount string `json:"change-count"`
+}
+
+type SupervisionJson struct {
+	Links    MechUnitsJsonLinks   `json:"_links"`
+	Embedded SupervisionJsonState `json:"_embedded"`
+}
+
+type SupervisionJsonState struct {
+	State []SupervisionJsonMeta `json:"_state"`
+}
+
+type SupervisionJsonMeta struct {
+	Type  string `json:"_type"`
+	Title string `json:"_title"`
+	Mode  string `json:"mode"`
+	Level string `json:"level"`
+}
+
+type MotionSupervision struct {
+	MechUnit    string
+	Enabled     bool
+	Sensitivity int
+}
+
+type PathSupervision struct {
+	MechUnit string
+	Enabled  bool
+	Level    int
+}
+
+type MechUnitJson struct {
+	Links    MechUnitsJsonLinks `json:"_links"`
+	Embedded MechUnitJsonState  `json:"_embedded"`
+}
+
+type MechUnitJsonState struct {
+	State []MechUnitJsonMeta `json:"_state"`
+}
+
+type MechUnitJsonMeta struct {
+	Type              string `json:"_type"`
+	Title             string `json:"_title"`
+	Mode              string `json:"mode"`
+	Tool              string `json:"tool-name"`
+	WorkObject        string `json:"wobj-name"`
+	Payload           string `json:"payload-name"`
+	TotalPayload      string `json:"total-payload-name"`
+	JogMode           string `json:"jog-mode"`
+	CoordSystem       string `json:"coord-system"`
+	HasIntegratedUnit string `json:"has-integrated-unit"`
+	Axes              string `json:"axes"`
+}
+
+type MastershipJson struct {
+	Links    MechUnitsJsonLinks  `json:"_links"`
+	Embedded MastershipJsonState `json:"_embedded"`
+}
+
+type MastershipJsonState struct {
+	State []MastershipJsonMeta `json:"_state"`
+}
+
+type MastershipJsonMeta struct {
+	Type       string `json:"_type"`
+	Title      string `json:"_title"`
+	Mastership string `json:"mastership"`
+	HeldByMe   string `json:"mastershipheldbyme"`
+}
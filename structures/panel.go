@@ -67,3 +67,71 @@ type OperationModeMeta struct {
 	Title  string `json:"_title"`
 	Opmode string `json:"opmode"`
 }
+
+type ControllerState struct {
+	Links    OperationModeLinks   `json:"_links"`
+	Embedded ControllerStateState `json:"_embedded"`
+}
+
+type ControllerStateState struct {
+	State []ControllerStateMeta `json:"_state"`
+}
+
+type ControllerStateMeta struct {
+	Type      string `json:"_type"`
+	Title     string `json:"_title"`
+	CtrlState string `json:"ctrlstate"`
+}
+
+type SpeedRatio struct {
+	Links    OperationModeLinks `json:"_links"`
+	Embedded SpeedRatioState    `json:"_embedded"`
+}
+
+type SpeedRatioState struct {
+	State []SpeedRatioMeta `json:"_state"`
+}
+
+type SpeedRatioMeta struct {
+	Type       string `json:"_type"`
+	Title      string `json:"_title"`
+	SpeedRatio string `json:"speedratio"`
+}
+
+// SubscriptionEventXML is a websocket event that can hold updates for several subscribed resources.
+type SubscriptionEventXML struct {
+	XMLName xml.Name              `xml:"html"`
+	Head    PanelHead             `xml:"head"`
+	Body    SubscriptionEventBody `xml:"body"`
+}
+
+type SubscriptionEventBody struct {
+	Div SubscriptionEventDiv `xml:"div"`
+}
+
+type SubscriptionEventDiv struct {
+	Poll []PanelLink         `xml:"a"`
+	List []SubscriptionEvent `xml:"ul>li"`
+}
+
+type SubscriptionEvent struct {
+	Class string        `xml:"class,attr"`
+	Title string        `xml:"title,attr"`
+	Link  PanelMetaLink `xml:"a"`
+	Span  []PanelSpan   `xml:"span"`
+}
+
+type CollisionDetectionState struct {
+	Links    OperationModeLinks           `json:"_links"`
+	Embedded CollisionDetectionStateState `json:"_embedded"`
+}
+
+type CollisionDetectionStateState struct {
+	State []CollisionDetectionStateMeta `json:"_state"`
+}
+
+type CollisionDetectionStateMeta struct {
+	Type        string `json:"_type"`
+	Title       string `json:"_title"`
+	ColdetState string `json:"coldetstate"`
+}
@@ -0,0 +1,91 @@
+package structures
+
+type RapidSymbolsJson struct {
+	Links    RapidJsonLinks        `json:"_links"`
+	Embedded RapidSymbolsJsonState `json:"_embedded"`
+}
+
+type RapidJsonLinks struct {
+	Base RapidJsonHref `json:"base"`
+	Next RapidJsonHref `json:"next"`
+}
+
+type RapidJsonHref struct {
+	Href string `json:"href"`
+}
+
+type RapidSymbolsJsonState struct {
+	State []RapidSymbolsJsonMeta `json:"_state"`
+}
+
+type RapidSymbolsJsonMeta struct {
+	Type     string `json:"_type"`
+	Title    string `json:"_title"`
+	Name     string `json:"name"`
+	SymbUrl  string `json:"symburl"`
+	SymbType string `json:"symtyp"`
+	DataType string `json:"dattyp"`
+}
+
+type RapidSymbolValueJson struct {
+	Links    RapidJsonLinks            `json:"_links"`
+	Embedded RapidSymbolValueJsonState `json:"_embedded"`
+}
+
+type RapidSymbolValueJsonState struct {
+	State []RapidSymbolValueJsonMeta `json:"_state"`
+}
+
+type RapidSymbolValueJsonMeta struct {
+	Type  string `json:"_type"`
+	Value string `json:"value"`
+}
+
+type RapidSymbol struct {
+	Name     string
+	Task     string
+	Module   string
+	SymbType string
+	DataType string
+}
+
+type Pose struct {
+	Trans [3]float64
+	Rot   [4]float64
+}
+
+type LoadData struct {
+	Mass float64
+	CoG  [3]float64
+	AoM  [4]float64
+	Ix   float64
+	Iy   float64
+	Iz   float64
+}
+
+type ToolData struct {
+	Name    string
+	Task    string
+	Module  string
+	RobHold bool
+	TFrame  Pose
+	TLoad   LoadData
+}
+
+type PayloadIssue struct {
+	Task     string
+	Module   string
+	Name     string
+	DataType string
+	Issue    string
+}
+
+type PayloadAudit struct {
+	RobotType string
+	// Capacity is the handling capacity in kg taken from the robot type, 0 if unknown.
+	Capacity float64
+	Tools    []ToolData
+	Loads    map[string]LoadData
+	// Issues lists the suspicious and unreadable tool and load data per task.
+	Issues map[string][]PayloadIssue
+}
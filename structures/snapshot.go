@@ -0,0 +1,44 @@
+package structures
+
+import "time"
+
+// IOSnapshot is a serializable capture of all IO signal values, simulation flags and device states.
+type IOSnapshot struct {
+	Time    time.Time        `json:"time"`
+	Signals []SignalSnapshot `json:"signals"`
+	Devices []DeviceSnapshot `json:"devices"`
+}
+
+type SignalSnapshot struct {
+	Path      string `json:"path"`
+	Type      string `json:"type"`
+	LValue    string `json:"lvalue"`
+	Simulated bool   `json:"simulated"`
+}
+
+type DeviceSnapshot struct {
+	Path   string `json:"path"`
+	LState string `json:"lstate"`
+	PState string `json:"pstate"`
+}
+
+type SignalChange struct {
+	Path string
+	Type string
+	// Before and After are empty when the signal was added or removed between the snapshots.
+	Before          string
+	After           string
+	SimulatedBefore bool
+	SimulatedAfter  bool
+}
+
+type DeviceChange struct {
+	Path   string
+	Before DeviceSnapshot
+	After  DeviceSnapshot
+}
+
+type IOSnapshotDiff struct {
+	Signals []SignalChange
+	Devices []DeviceChange
+}
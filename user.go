@@ -2,6 +2,7 @@ package abb
 
 import (
 	"bytes"
+	"encoding/json"
 	"encoding/xml"
 	"fmt"
 	"io"
@@ -183,3 +184,33 @@ func (c *Client) RegisterUser(Username string, Application string, Location stri
 	defer closeErrorCheck(resp.Body)
 	return nil
 }
+
+// GetRMMPState returns the RMMP privilege currently granted to this client.
+// Possible values: {none | modify | exec}
+func (c *Client) GetRMMPState() (string, error) {
+	var rmmp structures.RMMPState
+	c.Client = c.DigestAuthenticate()
+	req, err := http.NewRequest("GET", "http://"+c.Host+"/users/rmmp", nil)
+	if err != nil {
+		return "", err
+	}
+	q := req.URL.Query()
+	q.Add("json", "1")
+	req.URL.RawQuery = q.Encode()
+	resp, err := c.Client.Do(req)
+	if err != nil {
+		return "", err
+	}
+	if resp.StatusCode != http.StatusOK {
+		return "", fmt.Errorf("HTTP Status Code: %d", resp.StatusCode)
+	}
+	defer closeErrorCheck(resp.Body)
+	err = json.NewDecoder(resp.Body).Decode(&rmmp)
+	if err != nil {
+		return "", err
+	}
+	if len(rmmp.Embedded.State) == 0 {
+		return "", fmt.Errorf("RMMP State Not Found: %v", rmmp)
+	}
+	return rmmp.Embedded.State[0].Privilege, nil
+}